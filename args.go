@@ -0,0 +1,28 @@
+package exit
+
+import "fmt"
+
+// TooManyArgsError creates an ExitError with code CodeUsage signaling that a
+// command received got positional arguments while it accepts at most
+// maxArgs. Code recognizes the error as CodeUsage even if it is wrapped,
+// unless an outer ExitError carries a different code.
+func TooManyArgsError(got, maxArgs int) error {
+	return &argError{fmt.Sprintf("too many arguments: got %d, expected at most %d", got, maxArgs)}
+}
+
+// MissingArgError creates an ExitError with code CodeUsage signaling that the
+// required positional argument name is missing. Code recognizes the error as
+// CodeUsage even if it is wrapped, unless an outer ExitError carries a
+// different code.
+func MissingArgError(name string) error {
+	return &argError{"missing required argument: " + name}
+}
+
+// argError signals a problem with the positional arguments of a command.
+type argError struct {
+	msg string
+}
+
+func (e *argError) Error() string { return e.msg }
+
+func (e *argError) ExitCode() int { return CodeUsage }
@@ -0,0 +1,65 @@
+package exit
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestArgErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		msg  string
+	}{
+		{
+			name: "TooManyArgsError",
+			err:  TooManyArgsError(3, 1),
+			msg:  "too many arguments: got 3, expected at most 1",
+		},
+		{
+			name: "wrapped TooManyArgsError",
+			err:  wrapErr(TooManyArgsError(2, 0)),
+			msg:  "wrapped: too many arguments: got 2, expected at most 0",
+		},
+		{
+			name: "MissingArgError",
+			err:  MissingArgError("FILE"),
+			msg:  "missing required argument: FILE",
+		},
+		{
+			name: "wrapped MissingArgError",
+			err:  wrapErr(MissingArgError("FILE")),
+			msg:  "wrapped: missing required argument: FILE",
+		},
+		{
+			name: "re-wrapped TooManyArgsError",
+			err:  wrapErr(wrapErr(TooManyArgsError(2, 0))),
+			msg:  "wrapped: wrapped: too many arguments: got 2, expected at most 0",
+		},
+		{
+			name: "re-wrapped MissingArgError",
+			err:  fmt.Errorf("parse: %w", wrapErr(MissingArgError("FILE"))),
+			msg:  "parse: wrapped: missing required argument: FILE",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != CodeUsage {
+				t.Errorf("got code %d, want %d", code, CodeUsage)
+			}
+
+			if testCase.err.Error() != testCase.msg {
+				t.Errorf("got msg %q, want %q", testCase.err.Error(), testCase.msg)
+			}
+		})
+	}
+}
+
+func TestArgErrors_OuterExitError(t *testing.T) {
+	if code := Code(Error(CodeConfig, TooManyArgsError(2, 1))); code != CodeConfig {
+		t.Errorf("got code %d, want %d", code, CodeConfig)
+	}
+
+	if code := Code(Errorf(CodeSoftware, "parse: %w", wrapErr(MissingArgError("FILE")))); code != CodeSoftware {
+		t.Errorf("got code %d, want %d", code, CodeSoftware)
+	}
+}
@@ -0,0 +1,47 @@
+package exit
+
+import "fmt"
+
+var (
+	exitBanner      bool
+	lastExitPrinted bool
+)
+
+// SetExitBanner enables or disables printing of a standardized banner to
+// stderr when Exit is invoked with an error that produces a non-zero exit
+// code, e.g.:
+//
+//   Command failed with exit code 74 (CodeIOErr: input/output error)
+//
+// The banner is disabled by default. Calling SetExitBanner is not
+// goroutine-safe. Should be called early in main.
+func SetExitBanner(enabled bool) {
+	exitBanner = enabled
+}
+
+// LastExitPrinted reports whether the last call to Exit printed the message
+// of the error, e.g. via Fatal, Exitf or WithWriter, or the exit banner. It is
+// updated on every call to Exit and is already up to date when the functions
+// registered via OnExit run. This helps layered handlers to avoid printing
+// errors twice.
+func LastExitPrinted() bool {
+	return lastExitPrinted
+}
+
+// printBanner prints the exit banner for code if it is enabled and code is
+// non-zero. Reports whether the banner was printed.
+func printBanner(code int) bool {
+	if !exitBanner || code == CodeOK {
+		return false
+	}
+
+	info, ok := codeInfos[code]
+	if !ok {
+		info = codeInfo{"unknown", "unknown exit code"}
+	}
+
+	fmt.Fprintf(errWriter, "Command failed with exit code %d (%s: %s)\n",
+		code, info.name, info.description)
+
+	return true
+}
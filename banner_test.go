@@ -0,0 +1,141 @@
+package exit
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSetExitBanner(t *testing.T) {
+	SetExitBanner(true)
+	defer SetExitBanner(false)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "no error"},
+		{
+			name: "untyped error",
+			err:  errUntyped,
+			want: "Command failed with exit code 1 (CodeErr: generic error)\n",
+		},
+		{
+			name: "ExitError",
+			err:  Error(CodeIOErr, errUntyped),
+			want: "Command failed with exit code 74 (CodeIOErr: input/output error)\n",
+		},
+		{
+			name: "unknown code",
+			err:  Error(127, errUntyped),
+			want: "Command failed with exit code 127 (unknown: unknown exit code)\n",
+		},
+		{name: "nil error wrapped in ExitError", err: Error(CodeIOErr, nil)},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			captureExit(t)
+			buf := captureOutput(t)
+
+			Exit(testCase.err)
+
+			if got := buf.String(); got != testCase.want {
+				t.Errorf("got banner %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSetExitBanner_Disabled(t *testing.T) {
+	code := captureExit(t)
+	buf := captureOutput(t)
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if buf.Len() != 0 {
+		t.Errorf("got banner %q, want none", buf.String())
+	}
+
+	if *code != CodeIOErr {
+		t.Errorf("got code %d, want %d", *code, CodeIOErr)
+	}
+}
+
+func TestLastExitPrinted(t *testing.T) {
+	captureExit(t)
+	captureOutput(t)
+
+	var printedInHook bool
+
+	OnExit(func() { printedInHook = LastExitPrinted() })
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if LastExitPrinted() || printedInHook {
+		t.Error("banner disabled: expected LastExitPrinted to return false")
+	}
+
+	SetExitBanner(true)
+	defer SetExitBanner(false)
+
+	OnExit(func() { printedInHook = LastExitPrinted() })
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if !LastExitPrinted() || !printedInHook {
+		t.Error("banner enabled: expected LastExitPrinted to return true")
+	}
+
+	Exit(nil)
+
+	if LastExitPrinted() {
+		t.Error("success: expected LastExitPrinted to return false")
+	}
+}
+
+func TestLastExitPrinted_Error(t *testing.T) {
+	captureExit(t)
+	captureOutput(t)
+
+	var printedInHook bool
+
+	OnExit(func() { printedInHook = LastExitPrinted() })
+
+	Fatal(Error(CodeIOErr, errUntyped))
+
+	if !LastExitPrinted() || !printedInHook {
+		t.Error("Fatal: expected LastExitPrinted to return true")
+	}
+
+	Fatal(Error(CodeIOErr, errors.New("")))
+
+	if LastExitPrinted() {
+		t.Error("Fatal with empty message: expected LastExitPrinted to return false")
+	}
+
+	Exitf(CodeUsage, "bad flag %q", "-x")
+
+	if !LastExitPrinted() {
+		t.Error("Exitf: expected LastExitPrinted to return true")
+	}
+
+	Exit(errUntyped)
+
+	if LastExitPrinted() {
+		t.Error("Exit: expected LastExitPrinted to return false")
+	}
+
+	ExitCommand(errUntyped)
+
+	if !LastExitPrinted() {
+		t.Error("ExitCommand: expected LastExitPrinted to return true")
+	}
+
+	var buf bytes.Buffer
+
+	Exit(errUntyped, WithWriter(&buf))
+
+	if !LastExitPrinted() {
+		t.Error("WithWriter: expected LastExitPrinted to return true")
+	}
+}
@@ -0,0 +1,176 @@
+package exit
+
+import "errors"
+
+// walkErrors calls fn for err and every error in its chain in depth-first
+// order. Next to errors implementing Unwrap() error it also descends into all
+// branches of multi-errors implementing Unwrap() []error, e.g. those created
+// via errors.Join. Walking stops as soon as fn returns false.
+func walkErrors(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !fn(err) {
+		return false
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkErrors(e.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, branch := range e.Unwrap() {
+			if !walkErrors(branch, fn) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// findExitError finds the ExitError that determines the exit code of err and
+// returns it along with its code. In a linear chain of wrapped errors the
+// outermost ExitError wins. If the chain branches into a multi-error
+// implementing Unwrap() []error (e.g. created via errors.Join), each branch is
+// searched separately and the ExitError with the numerically highest code
+// wins. If multiple branches share the highest code, the first of them wins.
+//
+// Errors returned by custom As methods are only considered if err's chain
+// does not contain an ExitError otherwise, since errors.As is used as a last
+// resort.
+func findExitError(err error) (ExitError, int, bool) {
+	if exitErr, code, ok := walkExitError(err); ok {
+		return exitErr, code, true
+	}
+
+	var exitErr ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr, exitErrorCode(exitErr), true
+	}
+
+	return nil, 0, false
+}
+
+func walkExitError(err error) (ExitError, int, bool) {
+	if err == nil {
+		return nil, 0, false
+	}
+
+	if exitErr, ok := err.(ExitError); ok {
+		return exitErr, exitErrorCode(exitErr), true
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return walkExitError(e.Unwrap())
+	case interface{ Unwrap() []error }:
+		return highestExitError(e.Unwrap(), walkExitError)
+	}
+
+	return nil, 0, false
+}
+
+// highestExitError searches each of branches for an ExitError via find and
+// returns the one with the numerically highest code. If multiple branches
+// share the highest code, the first of them wins.
+func highestExitError(branches []error, find func(error) (ExitError, int, bool)) (ExitError, int, bool) {
+	var (
+		found    ExitError
+		highest  int
+		foundAny bool
+	)
+
+	for _, branch := range branches {
+		exitErr, code, ok := find(branch)
+		if ok && (!foundAny || code > highest) {
+			found, highest, foundAny = exitErr, code, true
+		}
+	}
+
+	return found, highest, foundAny
+}
+
+// CodeInnermost is like Code but uses the innermost ExitError in err's chain
+// instead of the outermost one. This is useful for callers who consider the
+// code attached closest to the original cause authoritative:
+//
+//   err := exit.Error(exit.CodeUnavailable, exit.Error(exit.CodeIOErr, err))
+//   exit.Code(err)          // CodeUnavailable
+//   exit.CodeInnermost(err) // CodeIOErr
+//
+// If the chain branches into a multi-error, the innermost ExitError of each
+// branch is determined and the numerically highest of their codes wins, like
+// it does for Code. Apart from choosing the ExitError, CodeInnermost applies
+// the same rules as Code, including error handlers, DetectTypedNil, the
+// precedence configured via SetCodePrecedence and ClampCodes. For example,
+// flag.ErrHelp still takes precedence over ExitError values by default.
+func CodeInnermost(err error) int {
+	cfg := newExitConfig()
+	cfg.innermost = true
+	return cfg.code(err)
+}
+
+// findInnermostExitError is like findExitError but finds the innermost
+// ExitError of a linear chain of wrapped errors.
+func findInnermostExitError(err error) (ExitError, int, bool) {
+	if exitErr, code, ok := walkInnermostExitError(err); ok {
+		return exitErr, code, true
+	}
+
+	var exitErr ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr, exitErrorCode(exitErr), true
+	}
+
+	return nil, 0, false
+}
+
+func walkInnermostExitError(err error) (ExitError, int, bool) {
+	if err == nil {
+		return nil, 0, false
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		if exitErr, code, ok := walkInnermostExitError(e.Unwrap()); ok {
+			return exitErr, code, true
+		}
+	case interface{ Unwrap() []error }:
+		if exitErr, code, ok := highestExitError(e.Unwrap(), walkInnermostExitError); ok {
+			return exitErr, code, true
+		}
+	}
+
+	if exitErr, ok := err.(ExitError); ok {
+		return exitErr, exitErrorCode(exitErr), true
+	}
+
+	return nil, 0, false
+}
+
+// ConflictingCodes returns all distinct exit codes of errors implementing
+// ExitError found in err's chain, including all branches of multi-errors, in
+// the order they were encountered. Codes are determined like Code does, e.g.
+// an *exec.ExitError of a process killed by signal N reports 128+N on Unix.
+// If the result contains more than one code, different layers disagree about
+// the exit code, which may indicate a wrapping bug. Returns nil if err is nil
+// or does not contain any ExitError.
+func ConflictingCodes(err error) []int {
+	var codes []int
+
+	seen := make(map[int]bool)
+
+	walkErrors(err, func(err error) bool {
+		if exitErr, ok := err.(ExitError); ok {
+			code := exitErrorCode(exitErr)
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+		return true
+	})
+
+	return codes
+}
@@ -0,0 +1,150 @@
+package exit
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// multiError is a minimal multi-error implementing Unwrap() []error.
+type multiError []error
+
+func (e multiError) Error() string   { return "multiple errors" }
+func (e multiError) Unwrap() []error { return e }
+
+func TestConflictingCodes(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want []int
+	}{
+		{name: "no error"},
+		{name: "untyped error", err: errUntyped},
+		{name: "single code", err: wrapErr(Error(CodeIOErr, errUntyped)), want: []int{CodeIOErr}},
+		{
+			name: "same code twice",
+			err:  Error(CodeIOErr, wrapErr(Error(CodeIOErr, errUntyped))),
+			want: []int{CodeIOErr},
+		},
+		{
+			name: "two codes",
+			err:  Error(CodeUnavailable, wrapErr(Error(CodeIOErr, errUntyped))),
+			want: []int{CodeUnavailable, CodeIOErr},
+		},
+		{
+			name: "multi-error branches",
+			err: wrapErr(multiError{
+				Error(CodeNoPerm, errUntyped),
+				errUntyped,
+				wrapErr(Error(CodeConfig, errUntyped)),
+			}),
+			want: []int{CodeNoPerm, CodeConfig},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := ConflictingCodes(testCase.err); !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("got %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCodeInnermost(t *testing.T) {
+	nested := Error(CodeUnavailable, wrapErr(Error(CodeIOErr, wrapErr(Error(CodeNoInput, errUntyped)))))
+
+	if code := Code(nested); code != CodeUnavailable {
+		t.Errorf("Code: expected outermost code %d, got %d", CodeUnavailable, code)
+	}
+
+	if code := CodeInnermost(nested); code != CodeNoInput {
+		t.Errorf("CodeInnermost: expected innermost code %d, got %d", CodeNoInput, code)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "nil", err: nil, code: CodeOK},
+		{name: "no exit error", err: wrapErr(errUntyped), code: CodeErr},
+		{name: "no exit error uses builtin rules", err: wrapErr(flag.ErrHelp), code: CodeHelpErr},
+		{name: "single exit error", err: wrapErr(Error(CodeConfig, errUntyped)), code: CodeConfig},
+		{name: "two levels", err: Error(CodeUnavailable, Error(CodeIOErr, errUntyped)), code: CodeIOErr},
+		{
+			name: "innermost of each branch, highest wins",
+			err: Error(CodeSoftware, multiError{
+				Error(CodeUsage, Error(CodeNoInput, errUntyped)),
+				Error(CodeOSErr, Error(CodeIOErr, errUntyped)),
+				errUntyped,
+			}),
+			code: CodeIOErr,
+		},
+		{
+			name: "multi-error without exit errors in branches",
+			err:  Error(CodeConfig, multiError{errUntyped, wrapErr(errUntyped)}),
+			code: CodeConfig,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := CodeInnermost(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestCodeInnermost_SharesRulesWithCode(t *testing.T) {
+	help := Error(CodeUsage, flag.ErrHelp)
+
+	if code, want := CodeInnermost(help), Code(help); code != want || code != CodeHelpErr {
+		t.Errorf("flag.ErrHelp: expected code %d, got %d", CodeHelpErr, code)
+	}
+
+	nested := Error(CodeUnavailable, Error(CodeIOErr, errUntyped))
+
+	t.Run("error handlers", func(t *testing.T) {
+		AddErrorHandler(func(err error) (int, bool) { return CodeConfig, true })
+		defer ClearErrorHandlers()
+
+		if code := CodeInnermost(nested); code != CodeConfig {
+			t.Errorf("expected code %d, got %d", CodeConfig, code)
+		}
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		defer SetCodePrecedence(nil)
+
+		if err := SetCodePrecedence([]RuleKind{RuleOS}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		err := Error(CodeUnavailable, Error(CodeIOErr, os.ErrNotExist))
+
+		if code := CodeInnermost(err); code != CodeNoInput {
+			t.Errorf("expected code %d, got %d", CodeNoInput, code)
+		}
+	})
+
+	t.Run("typed nil", func(t *testing.T) {
+		DetectTypedNil(true)
+		defer DetectTypedNil(false)
+
+		var typedNil *pointerError
+
+		if code := CodeInnermost(typedNil); code != CodeOK {
+			t.Errorf("expected code %d, got %d", CodeOK, code)
+		}
+	})
+
+	t.Run("clamp", func(t *testing.T) {
+		ClampCodes(true)
+		defer ClampCodes(false)
+
+		err := Error(CodeIOErr, Error(256+CodeNoInput, errUntyped))
+
+		if code, want := CodeInnermost(err), NormalizeCode(256+CodeNoInput); code != want {
+			t.Errorf("expected code %d, got %d", want, code)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package exit
+
+// CodeClass is a coarse category of exit codes. Classes are ordered by
+// severity, from least to most severe:
+//
+//   ClassSuccess < ClassTransient < ClassUsage < ClassPermanent
+type CodeClass int
+
+const (
+	// ClassSuccess is the class of CodeOK.
+	ClassSuccess CodeClass = iota
+	// ClassTransient is the class of temporary failures that may succeed on
+	// retry: CodeTempFail and CodeUnavailable.
+	ClassTransient
+	// ClassUsage is the class of errors caused by invoking the command
+	// incorrectly: CodeUsage and CodeHelpErr.
+	ClassUsage
+	// ClassPermanent is the class of all other non-zero exit codes.
+	ClassPermanent
+)
+
+// String implements fmt.Stringer.
+func (c CodeClass) String() string {
+	switch c {
+	case ClassSuccess:
+		return "success"
+	case ClassTransient:
+		return "transient"
+	case ClassUsage:
+		return "usage"
+	default:
+		return "permanent"
+	}
+}
+
+// ClassOf returns the CodeClass of code.
+func ClassOf(code int) CodeClass {
+	switch code {
+	case CodeOK:
+		return ClassSuccess
+	case CodeTempFail, CodeUnavailable:
+		return ClassTransient
+	case CodeUsage, CodeHelpErr:
+		return ClassUsage
+	default:
+		return ClassPermanent
+	}
+}
+
+// Classify returns the CodeClass of the exit code obtained from err via Code.
+func Classify(err error) CodeClass {
+	return ClassOf(Code(err))
+}
+
+// ExitByClass exits with the exit code of the error in errs whose CodeClass is
+// the most severe. If multiple errors share the most severe class, the first
+// of them is used. Unlike exiting with the numerically highest code, this
+// ensures that e.g. a permanent failure wins over a transient one. If all
+// errors are nil this is equivalent to os.Exit(0).
+//
+// See CodeClass for the severity ordering and Exit for more information.
+func ExitByClass(errs ...error) {
+	var (
+		worst error
+		code  int
+		class CodeClass
+	)
+
+	for _, err := range errs {
+		c := Code(err)
+		if cls := ClassOf(c); worst == nil || cls > class {
+			worst, code, class = err, c, cls
+		}
+	}
+
+	exit(worst, code, osExit, false)
+}
@@ -0,0 +1,60 @@
+package exit
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	for _, testCase := range []struct {
+		name  string
+		err   error
+		class CodeClass
+	}{
+		{name: "no error", class: ClassSuccess},
+		{name: "CodeTempFail", err: Error(CodeTempFail, errUntyped), class: ClassTransient},
+		{name: "CodeUnavailable", err: Error(CodeUnavailable, errUntyped), class: ClassTransient},
+		{name: "CodeUsage", err: Error(CodeUsage, errUntyped), class: ClassUsage},
+		{name: "untyped error", err: errUntyped, class: ClassPermanent},
+		{name: "CodeIOErr", err: Error(CodeIOErr, errUntyped), class: ClassPermanent},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := Classify(testCase.err); got != testCase.class {
+				t.Errorf("got %s, want %s", got, testCase.class)
+			}
+		})
+	}
+}
+
+func TestExitByClass(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		errs []error
+		code int
+	}{
+		{name: "no errors", code: CodeOK},
+		{name: "all nil", errs: []error{nil, nil}, code: CodeOK},
+		{
+			name: "permanent wins over numerically higher transient",
+			errs: []error{nil, Error(CodeTempFail, errUntyped), Error(CodeSoftware, errUntyped)},
+			code: CodeSoftware,
+		},
+		{
+			name: "usage wins over transient",
+			errs: []error{Error(CodeTempFail, errUntyped), Error(CodeUsage, errUntyped)},
+			code: CodeUsage,
+		},
+		{
+			name: "first of same class wins",
+			errs: []error{Error(CodeIOErr, errUntyped), Error(CodeConfig, errUntyped)},
+			code: CodeIOErr,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			ExitByClass(testCase.errs...)
+
+			if *code != testCase.code {
+				t.Errorf("got %d, want %d", *code, testCase.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package exit
+
+import "os"
+
+// processExitCode returns the exit code of the process described by state.
+// Returns -1 if the process has not exited.
+func processExitCode(state *os.ProcessState) int {
+	return state.ExitCode()
+}
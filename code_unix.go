@@ -0,0 +1,17 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import "os"
+
+// processExitCode returns the exit code of the process described by state. If
+// the process was terminated by signal N, it returns 128+N. Returns -1 if the
+// process has not exited.
+func processExitCode(state *os.ProcessState) int {
+	if code, ok := signalCode(state); ok {
+		return code
+	}
+
+	return state.ExitCode()
+}
@@ -0,0 +1,33 @@
+//go:build windows
+// +build windows
+
+package exit
+
+import (
+	"os"
+	"syscall"
+)
+
+// processExitCode returns the exit code of the process described by state.
+// Windows stores exit codes as uint32, e.g. 0xC0000005 for an access
+// violation. The code is passed through unchanged instead of being
+// reinterpreted as a negative value. On 32-bit platforms, codes which do not
+// fit into an int are taken modulo 256 like NormalizeCode does. Returns -1 if
+// the process has not exited.
+func processExitCode(state *os.ProcessState) int {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !state.Exited() {
+		return state.ExitCode()
+	}
+
+	code := uint64(status.ExitCode)
+	if code <= uint64(^uint(0)>>1) {
+		return int(code)
+	}
+
+	if code %= 256; code == 0 {
+		return CodeErr
+	}
+
+	return int(code)
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package exit
+
+import "testing"
+
+func TestCode_LargeWindowsExitCodes(t *testing.T) {
+	for _, code := range []int{1, 255, 256, 1000, 0x7FFFFFFF} {
+		err := execExitError(code)
+
+		if got := Code(err); got != code {
+			t.Errorf("expected code %d, got %d", code, got)
+		}
+
+		ClampCodes(true)
+		if got, want := Code(err), NormalizeCode(code); got != want {
+			t.Errorf("expected clamped code %d, got %d", want, got)
+		}
+		ClampCodes(false)
+	}
+}
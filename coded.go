@@ -0,0 +1,77 @@
+package exit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CodedError is a comparable error value which carries a message and an exit
+// code. Unlike the errors returned by Error or Errorf, it does not wrap
+// another error, so values can be compared via == and used as map keys or in
+// switch statements:
+//
+//   var ErrNoConfig = exit.CodedError{Message: "no config file found", Code: exit.CodeConfig}
+//
+//   switch err {
+//   case ErrNoConfig:
+//     // handle missing config
+//   }
+//
+// CodedError values can be serialized to and from JSON in the form
+// {"code":74,"message":"..."}, e.g. to pass errors from a child process to a
+// supervising process. See ParseExitError.
+//
+// Use CodedError for simple sentinel-like errors whose identity is defined by
+// their message and code. Use Error or Errorf to attach a code to an existing
+// error while preserving its chain.
+type CodedError struct {
+	Message string
+	Code    int
+}
+
+// Error implements error.
+func (e CodedError) Error() string { return e.Message }
+
+// ExitCode implements ExitError.
+func (e CodedError) ExitCode() int { return e.Code }
+
+// codedErrorJSON is the JSON representation of CodedError.
+type codedErrorJSON struct {
+	Code    *int   `json:"code"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e CodedError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(codedErrorJSON{Code: &e.Code, Message: e.Message})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It returns an error if data does
+// not contain a code.
+func (e *CodedError) UnmarshalJSON(data []byte) error {
+	var v codedErrorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.Code == nil {
+		return errors.New("missing exit code")
+	}
+
+	e.Code, e.Message = *v.Code, v.Message
+	return nil
+}
+
+// ParseExitError reconstructs an ExitError from its JSON representation as
+// produced by marshaling a CodedError. The first return value is a CodedError
+// carrying the parsed code and message. The second return value is non-nil
+// if data cannot be parsed.
+func ParseExitError(data []byte) (error, error) {
+	var e CodedError
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("parsing exit error: %w", err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,100 @@
+package exit
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCodedError(t *testing.T) {
+	errNoConfig := CodedError{Message: "no config", Code: CodeConfig}
+
+	if errNoConfig != (CodedError{Message: "no config", Code: CodeConfig}) {
+		t.Error("expected equal CodedError values to be ==")
+	}
+
+	if errNoConfig == (CodedError{Message: "no config", Code: CodeUsage}) {
+		t.Error("expected CodedError values with different codes not to be ==")
+	}
+
+	counts := map[error]int{}
+	counts[errNoConfig]++
+	counts[CodedError{Message: "no config", Code: CodeConfig}]++
+	counts[CodedError{Message: "no input", Code: CodeNoInput}]++
+
+	if len(counts) != 2 || counts[errNoConfig] != 2 {
+		t.Errorf("unexpected map contents: %v", counts)
+	}
+
+	var err error = errNoConfig
+
+	if msg := err.Error(); msg != "no config" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	if code := Code(wrapErr(err)); code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, code)
+	}
+
+	if !errors.Is(wrapErr(err), errNoConfig) {
+		t.Error("expected errors.Is to match equal CodedError values")
+	}
+}
+
+func TestCodedError_JSON(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  CodedError
+		json string
+	}{
+		{name: "simple", err: CodedError{Message: "i/o error", Code: CodeIOErr}, json: `{"code":74,"message":"i/o error"}`},
+		{name: "empty message", err: CodedError{Code: CodeUsage}, json: `{"code":64,"message":""}`},
+		{name: "zero code", err: CodedError{Message: "ok"}, json: `{"code":0,"message":"ok"}`},
+		{
+			name: "special characters",
+			err:  CodedError{Message: "quote \" backslash \\ newline \n tab \t unicode \u00e9 <html>&", Code: CodeDataErr},
+			json: `{"code":65,"message":"quote \" backslash \\ newline \n tab \t unicode é \u003chtml\u003e\u0026"}`,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := json.Marshal(testCase.err)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(data) != testCase.json {
+				t.Errorf("expected JSON %s, got %s", testCase.json, data)
+			}
+
+			parsed, err := ParseExitError(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var exitErr ExitError
+			if !errors.As(parsed, &exitErr) {
+				t.Fatalf("expected ExitError, got %T", parsed)
+			}
+
+			if code := exitErr.ExitCode(); code != testCase.err.Code {
+				t.Errorf("expected code %d, got %d", testCase.err.Code, code)
+			}
+
+			if msg := parsed.Error(); msg != testCase.err.Message {
+				t.Errorf("expected message %q, got %q", testCase.err.Message, msg)
+			}
+
+			if parsed != error(testCase.err) {
+				t.Errorf("expected %#v, got %#v", testCase.err, parsed)
+			}
+		})
+	}
+}
+
+func TestParseExitError_Invalid(t *testing.T) {
+	for _, data := range []string{``, `nope`, `{"message":"no code"}`, `{"code":"74"}`} {
+		if err, parseErr := ParseExitError([]byte(data)); parseErr == nil {
+			t.Errorf("expected parse error for %q, got %#v", data, err)
+		}
+	}
+}
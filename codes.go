@@ -1,5 +1,10 @@
 package exit
 
+import (
+	"strconv"
+	"strings"
+)
+
 const (
 	// Generic codes.
 	CodeOK      = 0 // success
@@ -22,4 +27,62 @@ const (
 	CodeProtocol    = 76 // remote error in protocol
 	CodeNoPerm      = 77 // permission denied
 	CodeConfig      = 78 // configuration error
+
+	// Codes following shell conventions.
+	CodeInterrupt  = 130 // terminated by SIGINT (128+2)
+	CodeTerminated = 143 // terminated by SIGTERM (128+15)
 )
+
+// codeInfo holds the symbolic name and description of an exit code.
+type codeInfo struct {
+	name        string
+	description string
+}
+
+// codeInfos contains name and description of all exit code constants.
+var codeInfos = map[int]codeInfo{
+	CodeOK:          {"CodeOK", "success"},
+	CodeErr:         {"CodeErr", "generic error"},
+	CodeHelpErr:     {"CodeHelpErr", "command is invoked with -help or -h flag but no such flag is defined"},
+	CodeUsage:       {"CodeUsage", "command line usage error"},
+	CodeDataErr:     {"CodeDataErr", "data format error"},
+	CodeNoInput:     {"CodeNoInput", "cannot open input"},
+	CodeNoUser:      {"CodeNoUser", "addressee unknown"},
+	CodeNoHost:      {"CodeNoHost", "host name unknown"},
+	CodeUnavailable: {"CodeUnavailable", "service unavailable"},
+	CodeSoftware:    {"CodeSoftware", "internal software error"},
+	CodeOSErr:       {"CodeOSErr", "system error (e.g., can't fork)"},
+	CodeOSFile:      {"CodeOSFile", "critical OS file missing"},
+	CodeCantCreat:   {"CodeCantCreat", "can't create (user) output file"},
+	CodeIOErr:       {"CodeIOErr", "input/output error"},
+	CodeTempFail:    {"CodeTempFail", "temp failure; user is invited to retry"},
+	CodeProtocol:    {"CodeProtocol", "remote error in protocol"},
+	CodeNoPerm:      {"CodeNoPerm", "permission denied"},
+	CodeConfig:      {"CodeConfig", "configuration error"},
+	CodeInterrupt:   {"CodeInterrupt", "terminated by SIGINT (128+2)"},
+	CodeTerminated:  {"CodeTerminated", "terminated by SIGTERM (128+15)"},
+}
+
+// Describe returns a human-readable name and description for code, e.g.
+// "IOErr: input/output error" for CodeIOErr. Returns "unknown" for codes that
+// do not correspond to any of the Code* constants.
+func Describe(code int) string {
+	info, ok := codeInfos[code]
+	if !ok {
+		return "unknown"
+	}
+
+	return strings.TrimPrefix(info.name, "Code") + ": " + info.description
+}
+
+// CodeString returns the name of the Code* constant corresponding to code,
+// e.g. "CodeIOErr" for CodeIOErr. Returns "Code(<n>)" for codes that do not
+// correspond to any of the Code* constants, e.g. "Code(3)".
+func CodeString(code int) string {
+	info, ok := codeInfos[code]
+	if !ok {
+		return "Code(" + strconv.Itoa(code) + ")"
+	}
+
+	return info.name
+}
@@ -0,0 +1,71 @@
+package exit
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	for _, testCase := range []struct {
+		code int
+		want string
+	}{
+		{code: CodeOK, want: "OK: success"},
+		{code: CodeErr, want: "Err: generic error"},
+		{code: CodeHelpErr, want: "HelpErr: command is invoked with -help or -h flag but no such flag is defined"},
+		{code: CodeUsage, want: "Usage: command line usage error"},
+		{code: CodeDataErr, want: "DataErr: data format error"},
+		{code: CodeNoInput, want: "NoInput: cannot open input"},
+		{code: CodeNoUser, want: "NoUser: addressee unknown"},
+		{code: CodeNoHost, want: "NoHost: host name unknown"},
+		{code: CodeUnavailable, want: "Unavailable: service unavailable"},
+		{code: CodeSoftware, want: "Software: internal software error"},
+		{code: CodeOSErr, want: "OSErr: system error (e.g., can't fork)"},
+		{code: CodeOSFile, want: "OSFile: critical OS file missing"},
+		{code: CodeCantCreat, want: "CantCreat: can't create (user) output file"},
+		{code: CodeIOErr, want: "IOErr: input/output error"},
+		{code: CodeTempFail, want: "TempFail: temp failure; user is invited to retry"},
+		{code: CodeProtocol, want: "Protocol: remote error in protocol"},
+		{code: CodeNoPerm, want: "NoPerm: permission denied"},
+		{code: CodeConfig, want: "Config: configuration error"},
+		{code: CodeInterrupt, want: "Interrupt: terminated by SIGINT (128+2)"},
+		{code: CodeTerminated, want: "Terminated: terminated by SIGTERM (128+15)"},
+		{code: 3, want: "unknown"},
+		{code: -1, want: "unknown"},
+	} {
+		if got := Describe(testCase.code); got != testCase.want {
+			t.Errorf("Describe(%d): got %q, want %q", testCase.code, got, testCase.want)
+		}
+	}
+}
+
+func TestCodeString(t *testing.T) {
+	for _, testCase := range []struct {
+		code int
+		want string
+	}{
+		{code: CodeOK, want: "CodeOK"},
+		{code: CodeErr, want: "CodeErr"},
+		{code: CodeHelpErr, want: "CodeHelpErr"},
+		{code: CodeUsage, want: "CodeUsage"},
+		{code: CodeDataErr, want: "CodeDataErr"},
+		{code: CodeNoInput, want: "CodeNoInput"},
+		{code: CodeNoUser, want: "CodeNoUser"},
+		{code: CodeNoHost, want: "CodeNoHost"},
+		{code: CodeUnavailable, want: "CodeUnavailable"},
+		{code: CodeSoftware, want: "CodeSoftware"},
+		{code: CodeOSErr, want: "CodeOSErr"},
+		{code: CodeOSFile, want: "CodeOSFile"},
+		{code: CodeCantCreat, want: "CodeCantCreat"},
+		{code: CodeIOErr, want: "CodeIOErr"},
+		{code: CodeTempFail, want: "CodeTempFail"},
+		{code: CodeProtocol, want: "CodeProtocol"},
+		{code: CodeNoPerm, want: "CodeNoPerm"},
+		{code: CodeConfig, want: "CodeConfig"},
+		{code: CodeInterrupt, want: "CodeInterrupt"},
+		{code: CodeTerminated, want: "CodeTerminated"},
+		{code: 3, want: "Code(3)"},
+		{code: -1, want: "Code(-1)"},
+	} {
+		if got := CodeString(testCase.code); got != testCase.want {
+			t.Errorf("CodeString(%d): got %q, want %q", testCase.code, got, testCase.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package exit
+
+// ExitCommand exits the program with the exit code for err returned by the
+// RunE function of a command, e.g. one of the github.com/spf13/cobra package.
+// If any of isUsage reports true for a non-nil err, the exit code will be
+// CodeUsage. Otherwise the exit code is obtained via Code. The message of err
+// is printed to stderr before exiting like Fatal does.
+//
+// Configure the root command with SilenceErrors and SilenceUsage, so that the
+// error is printed exactly once and usage is not printed for every error:
+//
+//   func main() {
+//     cmd := &cobra.Command{
+//       Use:           "app",
+//       SilenceErrors: true,
+//       SilenceUsage:  true,
+//       RunE:          run,
+//     }
+//
+//     exit.ExitCommand(cmd.Execute(), func(err error) bool {
+//       return strings.HasPrefix(err.Error(), "unknown flag")
+//     })
+//   }
+//
+// See Exit for more information.
+func ExitCommand(err error, isUsage ...func(error) bool) {
+	code := Code(err)
+
+	if err != nil {
+		for _, fn := range isUsage {
+			if fn(err) {
+				code = CodeUsage
+				break
+			}
+		}
+	}
+
+	exit(err, code, osExit, printError(errWriter, err))
+}
@@ -0,0 +1,46 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+type usageError struct{}
+
+func (usageError) Error() string { return "unknown flag: --foo" }
+
+func isUsageError(err error) bool {
+	var usageErr usageError
+	return errors.As(err, &usageErr)
+}
+
+func TestExitCommand(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		err     error
+		isUsage []func(error) bool
+		code    int
+		output  string
+	}{
+		{name: "nil", isUsage: []func(error) bool{isUsageError}, code: CodeOK},
+		{name: "usage error without predicate", err: usageError{}, code: CodeErr, output: "unknown flag: --foo\n"},
+		{name: "usage error", err: usageError{}, isUsage: []func(error) bool{isUsageError}, code: CodeUsage, output: "unknown flag: --foo\n"},
+		{name: "wrapped usage error", err: wrapErr(usageError{}), isUsage: []func(error) bool{isUsageError}, code: CodeUsage, output: "wrapped: unknown flag: --foo\n"},
+		{name: "other error", err: Error(CodeIOErr, errUntyped), isUsage: []func(error) bool{isUsageError}, code: CodeIOErr, output: "error\n"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+			buf := captureOutput(t)
+
+			ExitCommand(testCase.err, testCase.isUsage...)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+
+			if output := buf.String(); output != testCase.output {
+				t.Errorf("expected output %q, got %q", testCase.output, output)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package exit
+
+import "context"
+
+// ExitContext is like Exit but also takes the state of ctx into account. If
+// err is non-nil, it determines the exit code and ctx is ignored. If err is
+// nil but ctx is done, the exit code is determined by the context error: this
+// produces CodeInterrupt (130) for context.Canceled and CodeTempFail (75) for
+// context.DeadlineExceeded, unless error handlers map them differently. If
+// both are nil, the exit code is 0.
+//
+// On Go 1.20 and later, if ctx was canceled with a cause carrying an
+// ExitError via context.WithCancelCause, the code of the cause is used
+// instead. This allows to distinguish a shutdown triggered by SIGTERM from one
+// triggered by SIGINT:
+//
+//   ctx, cancel := context.WithCancelCause(context.Background())
+//
+//   go func() {
+//     <-sigterm
+//     cancel(exit.Errorf(exit.CodeTerminated, "received SIGTERM"))
+//   }()
+//
+//   exit.ExitContext(ctx, run(ctx))
+//
+// See Exit for more information.
+func ExitContext(ctx context.Context, err error) {
+	if err == nil {
+		err = doneError(ctx)
+	}
+
+	Exit(err)
+}
+
+// doneError returns the error describing why ctx is done, or nil if it is
+// not done yet. If the cause of ctx carries an ExitError, the cause is
+// returned instead of ctx.Err().
+func doneError(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	if cause := contextCause(ctx); cause != nil && cause != err {
+		if _, ok := ExplicitCode(cause); ok {
+			return cause
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build go1.20
+// +build go1.20
+
+package exit
+
+import "context"
+
+// contextCause returns the cause of ctx via context.Cause.
+func contextCause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
@@ -0,0 +1,11 @@
+//go:build !go1.20
+// +build !go1.20
+
+package exit
+
+import "context"
+
+// contextCause returns ctx.Err() as causes are not supported before Go 1.20.
+func contextCause(ctx context.Context) error {
+	return ctx.Err()
+}
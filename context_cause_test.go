@@ -0,0 +1,35 @@
+//go:build go1.20
+// +build go1.20
+
+package exit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExitContext_Cause(t *testing.T) {
+	for _, testCase := range []struct {
+		name  string
+		cause error
+		code  int
+	}{
+		{name: "coded cause", cause: Errorf(CodeTerminated, "received SIGTERM"), code: CodeTerminated},
+		{name: "wrapped coded cause", cause: wrapErr(Errorf(CodeTerminated, "received SIGTERM")), code: CodeTerminated},
+		{name: "untyped cause", cause: errUntyped, code: CodeInterrupt},
+		{name: "no cause", code: CodeInterrupt},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			cancel(testCase.cause)
+
+			ExitContext(ctx, nil)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+		})
+	}
+}
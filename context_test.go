@@ -0,0 +1,38 @@
+package exit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExitContext(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	for _, testCase := range []struct {
+		name string
+		ctx  context.Context
+		err  error
+		code int
+	}{
+		{name: "no error, context not done", ctx: context.Background(), code: CodeOK},
+		{name: "error, context not done", ctx: context.Background(), err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "no error, context canceled", ctx: canceled, code: CodeInterrupt},
+		{name: "no error, context deadline exceeded", ctx: expired, code: CodeTempFail},
+		{name: "error, context canceled", ctx: canceled, err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "untyped error, context canceled", ctx: canceled, err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			ExitContext(testCase.ctx, testCase.err)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+		})
+	}
+}
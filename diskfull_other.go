@@ -0,0 +1,8 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris,!windows
+
+package exit
+
+// diskFullErrors is empty as disk full errors cannot be detected on this
+// platform.
+var diskFullErrors []error
@@ -0,0 +1,10 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import "syscall"
+
+// diskFullErrors are the errors signaling that the disk is full or the quota
+// is exceeded.
+var diskFullErrors = []error{syscall.ENOSPC, syscall.EDQUOT}
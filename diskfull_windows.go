@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package exit
+
+import "syscall"
+
+const (
+	errorHandleDiskFull syscall.Errno = 39   // ERROR_HANDLE_DISK_FULL
+	errorDiskFull       syscall.Errno = 112  // ERROR_DISK_FULL
+	errorDiskQuota      syscall.Errno = 1295 // ERROR_DISK_QUOTA_EXCEEDED
+)
+
+// diskFullErrors are the errors signaling that the disk is full or the quota
+// is exceeded.
+var diskFullErrors = []error{errorHandleDiskFull, errorDiskFull, errorDiskQuota}
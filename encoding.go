@@ -0,0 +1,47 @@
+package exit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// EncodingErrorHandler is an ErrorHandlerFunc which maps errors that occur
+// while encoding or rendering output to CodeSoftware. Failing to encode the
+// program's own output is considered a bug of the program, unlike failing to
+// decode bad input, which is a data format error. It recognizes the following
+// errors:
+//
+//   - *json.UnsupportedTypeError, *json.UnsupportedValueError and
+//     *json.MarshalerError
+//   - *xml.UnsupportedTypeError
+//   - template.ExecError from text/template and html/template
+//   - *template.Error from html/template (e.g. escaping errors)
+//
+// It can be registered via AddErrorHandler:
+//
+//   exit.AddErrorHandler(exit.EncodingErrorHandler)
+func EncodingErrorHandler(err error) (code int, handled bool) {
+	var (
+		jsonTypeErr      *json.UnsupportedTypeError
+		jsonValueErr     *json.UnsupportedValueError
+		jsonMarshalerErr *json.MarshalerError
+		xmlTypeErr       *xml.UnsupportedTypeError
+		execErr          template.ExecError
+		htmlTemplateErr  *htmltemplate.Error
+	)
+
+	switch {
+	case errors.As(err, &jsonTypeErr),
+		errors.As(err, &jsonValueErr),
+		errors.As(err, &jsonMarshalerErr),
+		errors.As(err, &xmlTypeErr),
+		errors.As(err, &execErr),
+		errors.As(err, &htmlTemplateErr):
+		return CodeSoftware, true
+	default:
+		return 0, false
+	}
+}
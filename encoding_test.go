@@ -0,0 +1,56 @@
+package exit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"math"
+	"testing"
+	"text/template"
+)
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) { return nil, errors.New("boom") }
+
+func TestEncodingErrorHandler(t *testing.T) {
+	SetErrorHandler(EncodingErrorHandler)
+	defer SetErrorHandler(nil)
+
+	_, jsonTypeErr := json.Marshal(make(chan int))
+	_, jsonValueErr := json.Marshal(math.Inf(1))
+	_, jsonMarshalerErr := json.Marshal(failingMarshaler{})
+	_, xmlTypeErr := xml.Marshal(make(chan int))
+
+	execErr := template.Must(template.New("text").Parse("{{.Foo}}")).
+		Execute(ioutil.Discard, 42)
+	htmlErr := htmltemplate.Must(htmltemplate.New("html").Parse(`<a href="{{.}}`)).
+		Execute(ioutil.Discard, "foo")
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+		{name: "json unsupported type", err: jsonTypeErr, code: CodeSoftware},
+		{name: "json unsupported value", err: jsonValueErr, code: CodeSoftware},
+		{name: "wrapped json marshaler error", err: wrapErr(jsonMarshalerErr), code: CodeSoftware},
+		{name: "xml unsupported type", err: xmlTypeErr, code: CodeSoftware},
+		{name: "template exec error", err: execErr, code: CodeSoftware},
+		{name: "html template error", err: wrapErr(htmlErr), code: CodeSoftware},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if testCase.code != CodeOK && testCase.err == nil {
+				t.Fatal("expected test case to produce an error")
+			}
+
+			if got := Code(testCase.err); got != testCase.code {
+				t.Errorf("got %d, want %d (err: %v)", got, testCase.code, testCase.err)
+			}
+		})
+	}
+}
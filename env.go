@@ -0,0 +1,145 @@
+package exit
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CodeEnvVar is the name of the environment variable used by ExportCodeEnv
+// and ImportCodeEnv to pass an intended exit code to child processes.
+const CodeEnvVar = "EXIT_CODE"
+
+// Overridden in tests.
+var lookupEnv = os.LookupEnv
+
+// ExportCodeEnv returns an environment slice which communicates code to a
+// child process. It can be appended to the Env of an *exec.Cmd:
+//
+//   cmd.Env = append(os.Environ(), exit.ExportCodeEnv(exit.CodeIOErr)...)
+//
+// The child can read the code back via ImportCodeEnv.
+func ExportCodeEnv(code int) []string {
+	return []string{CodeEnvVar + "=" + strconv.Itoa(code)}
+}
+
+// ImportCodeEnv reads the exit code exported by a parent process via
+// ExportCodeEnv from the environment. The second return value is false if the
+// variable is unset or does not contain a valid integer.
+func ImportCodeEnv() (int, bool) {
+	value, ok := lookupEnv(CodeEnvVar)
+	if !ok {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+// RequireEnv returns an ExitError with code CodeConfig listing all of the
+// environment variables in names that are unset or empty. Returns nil if all
+// of them are set.
+func RequireEnv(names ...string) error {
+	var missing []string
+
+	for _, name := range names {
+		if value, ok := lookupEnv(name); !ok || value == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return Errorf(CodeConfig, "missing required environment variables: %s", strings.Join(missing, ", "))
+}
+
+// RequireEnvOrExit is like RequireEnv but exits with CodeConfig if any of the
+// environment variables in names is missing. Returns normally otherwise.
+//
+// See Exit for more information.
+func RequireEnvOrExit(names ...string) {
+	if err := RequireEnv(names...); err != nil {
+		Exit(err)
+	}
+}
+
+// LoadCodeOverridesFromEnv reads overrides for the exit code constants from
+// environment variables and appends an error handler like AddErrorHandler
+// which translates the codes determined by the builtin rules accordingly. This
+// allows to align the exit codes of a tool with site-specific conventions at
+// deploy time.
+//
+// The variable names consist of prefix, an underscore and the upper-cased
+// name of the constant without the Code prefix, e.g. with prefix "EXIT_CODE"
+// the variable EXIT_CODE_IOERR=80 makes errors which would produce CodeIOErr
+// produce 80 instead. CodeOK cannot be overridden.
+//
+// Returns an error listing all variables whose values are not valid exit
+// codes in the range 0-255. No handler is installed in this case. If no
+// override is configured, no handler is installed either.
+func LoadCodeOverridesFromEnv(prefix string) error {
+	prefix = strings.TrimSuffix(prefix, "_")
+
+	codes := make([]int, 0, len(codeInfos))
+	for code := range codeInfos {
+		if code != CodeOK {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Ints(codes)
+
+	overrides := make(map[int]int)
+
+	var invalid []string
+
+	for _, code := range codes {
+		name := prefix + "_" + strings.ToUpper(strings.TrimPrefix(codeInfos[code].name, "Code"))
+
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		override, err := strconv.Atoi(value)
+		if err != nil || !isValidCode(override) {
+			invalid = append(invalid, fmt.Sprintf("%s=%q", name, value))
+			continue
+		}
+
+		overrides[code] = override
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid exit code overrides: %s", strings.Join(invalid, ", "))
+	}
+
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	defaultMapper.Add(codeOverrides(overrides))
+
+	return nil
+}
+
+// codeOverrides is a Handler which translates the codes determined by the
+// builtin rules.
+type codeOverrides map[int]int
+
+func (o codeOverrides) Code(err error) (int, bool) {
+	return o.codeWithFallback(err, defaultErrorCode)
+}
+
+func (o codeOverrides) codeWithFallback(err error, fallback int) (int, bool) {
+	code, ok := o[builtinCode(err, fallback)]
+	return code, ok
+}
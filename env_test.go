@@ -0,0 +1,140 @@
+package exit
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeEnv replaces lookupEnv for the duration of the test with a lookup in
+// env, which contains entries of the form "key=value".
+func fakeEnv(t *testing.T, env []string) {
+	lookupEnv = func(key string) (string, bool) {
+		for _, kv := range env {
+			if strings.HasPrefix(kv, key+"=") {
+				return kv[len(key)+1:], true
+			}
+		}
+		return "", false
+	}
+	t.Cleanup(func() { lookupEnv = os.LookupEnv })
+}
+
+func TestExportCodeEnv(t *testing.T) {
+	env := ExportCodeEnv(CodeIOErr)
+	if len(env) != 1 || env[0] != "EXIT_CODE=74" {
+		t.Errorf("got %v, want [EXIT_CODE=74]", env)
+	}
+
+	fakeEnv(t, append([]string{"FOO=bar"}, env...))
+
+	code, ok := ImportCodeEnv()
+	if !ok || code != CodeIOErr {
+		t.Errorf("got (%d, %t), want (%d, true)", code, ok, CodeIOErr)
+	}
+}
+
+func TestImportCodeEnv(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		env  []string
+		code int
+		ok   bool
+	}{
+		{name: "unset"},
+		{name: "invalid", env: []string{"EXIT_CODE=foo"}},
+		{name: "valid", env: []string{"EXIT_CODE=78"}, code: CodeConfig, ok: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeEnv(t, testCase.env)
+
+			code, ok := ImportCodeEnv()
+			if code != testCase.code || ok != testCase.ok {
+				t.Errorf("got (%d, %t), want (%d, %t)", code, ok, testCase.code, testCase.ok)
+			}
+		})
+	}
+}
+
+func TestRequireEnv(t *testing.T) {
+	fakeEnv(t, []string{"FOO=foo", "BAR=bar", "EMPTY="})
+
+	if err := RequireEnv("FOO", "BAR"); err != nil {
+		t.Errorf("all present: got %#v, want nil", err)
+	}
+
+	err := RequireEnv("FOO", "BAZ", "BAR", "EMPTY", "QUX")
+	if code := Code(err); code != CodeConfig {
+		t.Errorf("got code %d, want %d", code, CodeConfig)
+	}
+
+	if want := "missing required environment variables: BAZ, EMPTY, QUX"; err.Error() != want {
+		t.Errorf("got msg %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRequireEnvOrExit(t *testing.T) {
+	fakeEnv(t, []string{"FOO=foo"})
+
+	code := captureExit(t)
+
+	RequireEnvOrExit("FOO")
+
+	if *code != -1 {
+		t.Errorf("all present: expected no exit, got code %d", *code)
+	}
+
+	RequireEnvOrExit("FOO", "BAR")
+
+	if *code != CodeConfig {
+		t.Errorf("got code %d, want %d", *code, CodeConfig)
+	}
+}
+
+func TestLoadCodeOverridesFromEnv(t *testing.T) {
+	defer ClearErrorHandlers()
+
+	fakeEnv(t, []string{"MYAPP_EXIT_IOERR=80", "MYAPP_EXIT_TEMPFAIL=100", "OTHER_EXIT_NOPERM=90"})
+
+	if err := LoadCodeOverridesFromEnv("MYAPP_EXIT"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "overridden ExitError", err: wrapErr(Error(CodeIOErr, errUntyped)), code: 80},
+		{name: "overridden builtin rule", err: wrapErr(context.DeadlineExceeded), code: 100},
+		{name: "not overridden", err: Error(CodeNoPerm, errUntyped), code: CodeNoPerm},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestLoadCodeOverridesFromEnv_Invalid(t *testing.T) {
+	defer ClearErrorHandlers()
+
+	fakeEnv(t, []string{"MYAPP_EXIT_IOERR=80", "MYAPP_EXIT_USAGE=nope", "MYAPP_EXIT_CONFIG=256"})
+
+	err := LoadCodeOverridesFromEnv("MYAPP_EXIT_")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if msg, want := err.Error(), `invalid exit code overrides: MYAPP_EXIT_USAGE="nope", MYAPP_EXIT_CONFIG="256"`; msg != want {
+		t.Errorf("expected message %q, got %q", want, msg)
+	}
+
+	if code := Code(Error(CodeIOErr, errUntyped)); code != CodeIOErr {
+		t.Errorf("expected no overrides to be installed, got code %d", code)
+	}
+}
@@ -0,0 +1,65 @@
+//go:build go1.19
+// +build go1.19
+
+package exit
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// EscalatingError wraps err with an ExitError whose code depends on the
+// current value of counter, e.g. the number of failures observed by a
+// degraded-mode server. The keys of thresholds are counter values, the values
+// are the exit codes to use once the counter reached the respective value.
+// ExitCode returns the code of the highest threshold not exceeding the
+// counter or CodeErr if the counter is below all thresholds. If err is nil it
+// is returned as is.
+//
+// The counter is read atomically each time ExitCode is called, so the error
+// reflects later changes of the counter. It is safe to call ExitCode
+// concurrently.
+func EscalatingError(counter *atomic.Int64, thresholds map[int64]int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	levels := make([]escalationLevel, 0, len(thresholds))
+	for threshold, code := range thresholds {
+		levels = append(levels, escalationLevel{threshold, code})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].threshold < levels[j].threshold
+	})
+
+	return &escalatingError{err, counter, levels}
+}
+
+type escalationLevel struct {
+	threshold int64
+	code      int
+}
+
+type escalatingError struct {
+	error
+	counter *atomic.Int64
+	levels  []escalationLevel
+}
+
+func (e *escalatingError) Unwrap() error { return e.error }
+
+func (e *escalatingError) ExitCode() int {
+	value := e.counter.Load()
+	code := CodeErr
+
+	for _, level := range e.levels {
+		if value < level.threshold {
+			break
+		}
+
+		code = level.code
+	}
+
+	return code
+}
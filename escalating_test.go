@@ -0,0 +1,40 @@
+//go:build go1.19
+// +build go1.19
+
+package exit
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestEscalatingError(t *testing.T) {
+	var counter atomic.Int64
+
+	if err := EscalatingError(&counter, nil, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	err := EscalatingError(&counter, map[int64]int{
+		10: CodeUnavailable,
+		3:  CodeTempFail,
+	}, errUntyped)
+
+	for _, testCase := range []struct {
+		value int64
+		code  int
+	}{
+		{value: 0, code: CodeErr},
+		{value: 2, code: CodeErr},
+		{value: 3, code: CodeTempFail},
+		{value: 9, code: CodeTempFail},
+		{value: 10, code: CodeUnavailable},
+		{value: 100, code: CodeUnavailable},
+	} {
+		counter.Store(testCase.value)
+
+		if code := Code(wrapErr(err)); code != testCase.code {
+			t.Errorf("counter %d: got code %d, want %d", testCase.value, code, testCase.code)
+		}
+	}
+}
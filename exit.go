@@ -72,9 +72,11 @@ package exit
 
 import (
 	"errors"
-	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"time"
 )
 
 // ExitError is an error that can signal the desired exit code. It is
@@ -86,6 +88,10 @@ type ExitError interface {
 
 // Error wraps err with an ExitError that returns given code. If err is nil it
 // is returned as is.
+//
+// Since Code uses the outermost ExitError in an error's chain, the code
+// overrides any code carried by err itself. See WithCode for attaching a code
+// only if err does not carry one yet.
 func Error(code int, err error) error {
 	if err == nil {
 		return nil
@@ -94,6 +100,44 @@ func Error(code int, err error) error {
 	return &exitError{err, code}
 }
 
+// WithCode attaches code to err only if err's chain does not contain an
+// ExitError yet. Otherwise err is returned as is, preserving its code. If err
+// is nil it is returned as is.
+func WithCode(code int, err error) error {
+	var exitErr ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		return err
+	}
+
+	return &exitError{err, code}
+}
+
+// OverrideCode wraps err with an ExitError that returns given code regardless
+// of any ExitError contained in err's chain, e.g. to surface an
+// *exec.ExitError with code 2 as CodeUnavailable. The original error can still
+// be obtained via errors.Unwrap. If err is nil it is returned as is.
+//
+// OverrideCode is equivalent to Error and exists to make the intent explicit
+// at the call site.
+func OverrideCode(code int, err error) error {
+	return Error(code, err)
+}
+
+// Tag attaches code to err without altering it otherwise: the message of the
+// returned error equals err.Error(), errors.Is(tagged, err) is true and
+// errors.Is and errors.As see through it as if err was passed directly. This
+// is useful for decorating errors of third-party packages. If err is nil it
+// is returned as is.
+//
+// Tag is equivalent to Error, which already has these properties. It is
+// provided for readability where the intent is to decorate an error without
+// changing it.
+//
+// Tag is unrelated to Tagged, which declares exit codes via struct tags.
+func Tag(code int, err error) error {
+	return Error(code, err)
+}
+
 // Errorf creates a new error and wraps it into an ExitError with given exit
 // code. Format and args are used to build the wrapped error via fmt.Errorf.
 func Errorf(code int, format string, args ...interface{}) error {
@@ -113,6 +157,79 @@ func Errorp(code int, err *error) {
 	*err = Error(code, *err)
 }
 
+// Errorpf is like Errorp but also adds context to the pointed-to error. If the
+// value of err is non-nil, it is wrapped via fmt.Errorf using format and args
+// followed by ": " and the original error, then wrapped with an ExitError
+// with given code. If the value of err is nil it is left untouched.
+//
+// Example:
+//
+//   defer exit.Errorpf(exit.CodeIOErr, &err, "writing %s", path)
+//
+// See Error for more information.
+func Errorpf(code int, err *error, format string, args ...interface{}) {
+	if *err == nil {
+		return
+	}
+
+	*err = Error(code, fmt.Errorf(format+": %w", append(args, *err)...))
+}
+
+// CaptureCode sets the pointed-to code to the exit code of the pointed-to
+// error. Can be used in defer statements to record the exit code a function
+// returns with, e.g. for logging. It never exits.
+//
+// Example:
+//
+//   func foo() (err error) {
+//     var code int
+//     defer func() { log.Printf("foo finished with exit code %d", code) }()
+//     defer exit.CaptureCode(&err, &code)
+//
+//     return someOperation()
+//   }
+//
+// See Code for more information.
+func CaptureCode(err *error, code *int) {
+	*code = Code(*err)
+}
+
+// ContextError wraps err with additional context while preserving the exit
+// code of any ExitError (e.g. *exec.ExitError) in err's chain. The message is
+// built from format and args followed by ": " and the message of err. If err
+// is nil it is returned as is.
+//
+// The returned error implements ExitError by delegating to the inner
+// ExitError. If err's chain does not contain an ExitError, the returned error
+// does not implement ExitError either.
+//
+// Example:
+//
+//   return exit.ContextError(cmd.Run(), "running %s", cmd.Path)
+func ContextError(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	wrapped := fmt.Errorf(format+": %w", append(args, err)...)
+
+	var exitErr ExitError
+	if !errors.As(err, &exitErr) {
+		return wrapped
+	}
+
+	return &contextError{wrapped, exitErr}
+}
+
+type contextError struct {
+	error
+	inner ExitError
+}
+
+func (e *contextError) Unwrap() error { return errors.Unwrap(e.error) }
+
+func (e *contextError) ExitCode() int { return exitErrorCode(e.inner) }
+
 type exitError struct {
 	error
 	code int
@@ -125,46 +242,157 @@ func (e *exitError) ExitCode() int { return e.code }
 // Code picks a suitable exit code for err. If err is nil the returned code
 // is 0. Otherwise it attempts to provide a meaningful exit code for err.
 //
-// If a custom error handler func was set via SetErrorHandler and it is
-// non-nil, this func is executed first to determine a suitable exit code if
-// err is non-nil. Otherwise it proceeds to determine the exit code by the
-// builtin rules below.
+// If custom error handler funcs were set via SetErrorHandler or
+// AddErrorHandler, these funcs are executed first in the order they were
+// added to determine a suitable exit code if err is non-nil. The first
+// handler that handles err wins. Otherwise it proceeds to determine the exit
+// code by the builtin rules below.
 //
 // Uses the standard library's errors.Is and errors.As functions to also
-// inspect wrapped errors.
+// inspect wrapped errors. In short, the lookup order is: error handlers,
+// flag.ErrHelp, ExitError, argument errors, Code() int methods, RegisterType
+// mappings, code extractors, the remaining builtin rules and finally the
+// default code. The order of the builtin rules can be changed via
+// SetCodePrecedence.
+//
+// If err contains flag.ErrHelp the exit code will be 2 unless a different code
+// was configured via SetHelpExitCode. flag.ErrHelp deliberately takes
+// precedence over ExitError values and Code() int methods: earlier versions
+// of this package always produced exit code 2 for errors containing
+// flag.ErrHelp, even if they were wrapped in an ExitError, and this is kept
+// for compatibility. Use SetCodePrecedence to move RuleHelp after
+// RuleExitError and RuleCodeMethod if explicit codes should win.
+//
+// If err contains an error created via TooManyArgsError or MissingArgError,
+// the exit code will be 64, even if it is wrapped. An outer ExitError carrying
+// a different code still takes precedence.
 //
 // If an error implements ExitError (e.g. *exec.ExitError) the value
-// returned by err.ExitCode() will be returned.
+// returned by err.ExitCode() will be returned. As an exception, if an
+// *exec.ExitError indicates that the process was terminated by signal N, the
+// exit code will be 128+N following the shell convention. This is only
+// supported on Unix platforms. On Windows, where exit codes are stored as
+// uint32, large codes like 0xC0000005 are passed through unchanged unless
+// ClampCodes is enabled.
 //
-// If err contains flag.ErrHelp the exit code will be 2.
+// If multiple errors in err's chain implement ExitError, the outermost one
+// wins. Use CodeInnermost to prefer the innermost one instead. If err
+// contains a multi-error (e.g. created via errors.Join) whose branches carry
+// different exit codes, the numerically highest code wins, regardless of the
+// order of the branches. For sysexits codes this usually corresponds to the
+// most specific failure.
 //
-// All other errors produce exit code 1.
+// If err does not contain an ExitError but an error implementing a Code() int
+// method, the value returned by that method will be returned.
+//
+// Then mappings registered via RegisterType are consulted, followed by the
+// code extractors registered via RegisterCodeExtractor, both in order of
+// their registration.
+//
+// If err contains an error implementing StatusCoder, the exit code is
+// obtained by passing its HTTP status code to FromHTTPStatus. Successful 2xx
+// statuses are ignored.
+//
+// If err contains context.DeadlineExceeded the exit code will be 75. If err
+// contains context.Canceled the exit code will be 130.
+//
+// If err contains a net.Error the exit code will be 75 if it is a timeout and
+// 69 otherwise.
+//
+// If err contains one of the following sentinel errors of the os package, the
+// exit code is chosen according to this table:
+//
+//   os.ErrNotExist   -> 66 (CodeNoInput)
+//   os.ErrPermission -> 77 (CodeNoPerm)
+//   os.ErrExist      -> 73 (CodeCantCreat)
+//
+// If err signals that the disk is full or the quota is exceeded (e.g. ENOSPC
+// or EDQUOT on Unix, ERROR_DISK_FULL or ERROR_DISK_QUOTA_EXCEEDED on Windows),
+// the exit code will be 73 (CodeCantCreat), regardless of the operation that
+// failed.
+//
+// Otherwise, if err contains an *os.PathError (or *fs.PathError, which is the
+// same type) of one of the following operations, the operation determines the
+// exit code before the sentinel errors above are considered:
+//
+//   "open"   -> 66 (CodeNoInput)
+//   "create" -> 73 (CodeCantCreat)
+//   "mkdir"  -> 73 (CodeCantCreat)
+//   "write"  -> 74 (CodeIOErr)
+//
+// Path errors wrapping os.ErrPermission or os.ErrExist, and "open" errors
+// wrapping os.ErrNotExist, keep the codes of these sentinels, e.g. a
+// permission denied error produces exit code 77 regardless of the operation,
+// and os.OpenFile with O_CREATE|O_EXCL on an existing file produces exit code
+// 73. Failures of all other operations are classified by the sentinel errors
+// alone.
+//
+// If err contains one of the errors returned by the os/user package for
+// unknown users or groups (e.g. user.UnknownUserError) the exit code will be
+// 67.
+//
+// All other errors produce exit code 1 unless a different default was
+// configured via SetDefaultErrorCode.
+//
+// If enabled via DetectTypedNil, typed nil errors produce exit code 0.
+//
+// If enabled via ClampCodes, the resulting exit code is normalized via
+// NormalizeCode.
+//
+// Use Explain to find out which rule determined the exit code for err.
 func Code(err error) int {
-	if err != nil && errorHandlerFn != nil {
-		if code, handled := errorHandlerFn(err); handled {
-			return code
-		}
-	}
+	return newExitConfig().code(err)
+}
 
-	var exitErr ExitError
+// ExplicitCode returns the exit code of the ExitError in err's chain that
+// Code would use.
+// Unlike Code it does not apply any handlers or fallback rules, so the second
+// return value is false if err does not contain an ExitError, allowing callers
+// to distinguish "no explicit code" from a generic error. Returns (0, false)
+// if err is nil.
+func ExplicitCode(err error) (code int, ok bool) {
+	_, code, ok = findExitError(err)
+	return code, ok
+}
 
-	switch {
-	case err == nil:
-		return CodeOK
-	case errors.Is(err, flag.ErrHelp):
-		return CodeHelpErr
-	case errors.As(err, &exitErr):
-		return exitErr.ExitCode()
-	default:
-		return CodeErr
-	}
+// DefaultHandler determines the exit code for err using only the builtin
+// rules described in Code. It reports err as handled if one of the builtin
+// rules matched. Otherwise it returns the default code configured via
+// SetDefaultErrorCode and reports false, so that a handler passing on the
+// result leaves it to Code or Exit to apply the default code in effect for
+// the call, e.g. the one set via WithDefaultCode. It never
+// consults the error handlers set via SetErrorHandler or AddErrorHandler, so
+// custom handlers can safely delegate to it without causing infinite
+// recursion, e.g. to log every mapping decision:
+//
+//   exit.SetErrorHandler(func(err error) (int, bool) {
+//     code, handled := exit.DefaultHandler(err)
+//     log.Printf("mapped %v to exit code %d", err, code)
+//     return code, handled
+//   })
+//
+// Codes are not normalized, even if ClampCodes is enabled.
+func DefaultHandler(err error) (code int, handled bool) {
+	d := builtinDecision(err, defaultErrorCode)
+	return d.Code, d.Rule != RuleFallback
 }
 
 var (
 	// Overridden in tests.
-	osExit = os.Exit
+	osExit              = os.Exit
+	errWriter io.Writer = defaultErrWriter
+	sleep               = time.Sleep
+
+	defaultMapper = &Mapper{}
 
-	errorHandlerFn ErrorHandlerFunc
+	finalCodeHook    FinalCodeHookFunc
+	exitLogFunc      ExitLogFunc
+	exitDelay        time.Duration
+	clampCodes       bool
+	detectTypedNil   bool
+	codeExtractors   []CodeExtractorFunc
+	defaultErrorCode = CodeErr
+	helpExitCode     = CodeHelpErr
 )
 
 // ErrorHandlerFunc may provide an exit code for err. If it determined a
@@ -177,18 +405,412 @@ type ErrorHandlerFunc func(err error) (code int, handled bool)
 // that it handled an error by returning true as its second return value the
 // exit code is determined using the builtin rules.
 //
-// Calling SetErrorHandler is not goroutine-safe. Should be called early in
-// main.
+// SetErrorHandler replaces all error handlers previously added via
+// AddErrorHandler or set via SetErrorHandler2. Passing nil removes all error
+// handlers. See SetErrorHandler2 for handlers which receive the exit code
+// determined by the builtin rules.
+//
+// SetErrorHandler is goroutine-safe and may be called concurrently with Code.
 //
 // See Code for more information.
 func SetErrorHandler(fn ErrorHandlerFunc) {
-	errorHandlerFn = fn
+	if fn == nil {
+		defaultMapper.Set()
+		return
+	}
+
+	defaultMapper.Set(HandlerFunc(fn))
+}
+
+// ErrorHandlerFunc2 is like ErrorHandlerFunc but additionally receives the
+// exit code the builtin rules determined for err as defaultCode. This allows
+// to adjust the default instead of reimplementing the classification.
+type ErrorHandlerFunc2 func(err error, defaultCode int) (code int, handled bool)
+
+// SetErrorHandler2 is like SetErrorHandler but sets an error handler which
+// receives the exit code determined by the builtin rules for the error
+// alongside it:
+//
+//   exit.SetErrorHandler2(func(err error, defaultCode int) (int, bool) {
+//     if defaultCode == exit.CodeIOErr {
+//       return 80, true
+//     }
+//
+//     return defaultCode, false
+//   })
+//
+// If no builtin rule matches, defaultCode is the default code in effect for
+// the call, i.e. the one set via WithDefaultCode or else the one configured
+// via SetDefaultErrorCode. If fn does not signal that it handled an error by
+// returning true as its second return value, the exit code determined by the
+// builtin rules is used.
+//
+// Like SetErrorHandler, SetErrorHandler2 replaces all error handlers
+// previously set via SetErrorHandler or SetErrorHandler2, or added via
+// AddErrorHandler or LoadCodeOverridesFromEnv. Handlers added afterwards via
+// AddErrorHandler or LoadCodeOverridesFromEnv are appended after fn, so fn is
+// consulted first. Passing nil removes all error handlers.
+//
+// SetErrorHandler2 is goroutine-safe and may be called concurrently with Code.
+//
+// See Code for more information.
+func SetErrorHandler2(fn ErrorHandlerFunc2) {
+	if fn == nil {
+		defaultMapper.Set()
+		return
+	}
+
+	defaultMapper.Set(errorHandler2(fn))
+}
+
+// errorHandler2 adapts an ErrorHandlerFunc2 to the Handler interface.
+type errorHandler2 ErrorHandlerFunc2
+
+func (fn errorHandler2) Code(err error) (int, bool) {
+	return fn.codeWithFallback(err, defaultErrorCode)
+}
+
+func (fn errorHandler2) codeWithFallback(err error, fallback int) (int, bool) {
+	return fn(err, builtinCode(err, fallback))
+}
+
+// AddErrorHandler appends fn to the list of custom error handlers. This
+// allows multiple subsystems to contribute their own mapping of errors to
+// exit codes. Passing nil is a no-op.
+//
+// When Code or Exit are invoked with a non-nil error, the error handlers are
+// called in the order they were added. The first handler that signals that it
+// handled the error by returning true as its second return value determines
+// the exit code and the remaining handlers are not called. If no handler
+// handles the error, the exit code is determined using the builtin rules.
+//
+// AddErrorHandler is goroutine-safe and may be called concurrently with Code.
+//
+// See Code for more information.
+func AddErrorHandler(fn ErrorHandlerFunc) {
+	if fn != nil {
+		defaultMapper.Add(HandlerFunc(fn))
+	}
+}
+
+// ClearErrorHandlers removes all custom error handlers set via
+// SetErrorHandler, SetErrorHandler2 or AddErrorHandler.
+//
+// ClearErrorHandlers is goroutine-safe.
+func ClearErrorHandlers() {
+	defaultMapper.Set()
+}
+
+// exitErrorCode returns the exit code of exitErr. If exitErr is an
+// *exec.ExitError, the exit code is read from its process state in a platform
+// specific way: on Unix platforms a process that was terminated by signal N
+// produces 128+N instead of -1, on Windows exit codes are treated as uint32.
+func exitErrorCode(exitErr ExitError) int {
+	if execErr, ok := exitErr.(*exec.ExitError); ok && execErr.ProcessState != nil {
+		return processExitCode(execErr.ProcessState)
+	}
+
+	return exitErr.ExitCode()
+}
+
+// ClampCodes controls whether Code normalizes exit codes to the range 0-255
+// via NormalizeCode. Disabled by default.
+//
+// Calling ClampCodes is not goroutine-safe. Should be called early in main.
+func ClampCodes(enabled bool) {
+	clampCodes = enabled
+}
+
+// DetectTypedNil controls whether Code treats typed nil errors like nil
+// errors and returns 0 for them. A typed nil is a non-nil error interface
+// wrapping a nil pointer, map, slice, func or chan value, which usually
+// happens when a function returns a nil *MyError as error. Detection uses
+// reflection and is therefore disabled by default. See MustExit for a
+// variant of Exit which always detects typed nils.
+//
+// Calling DetectTypedNil is not goroutine-safe. Should be called early in
+// main.
+func DetectTypedNil(enabled bool) {
+	detectTypedNil = enabled
+}
+
+// NormalizeCode normalizes code to the range 0-255 which is supported by
+// os.Exit on all platforms. On POSIX systems os.Exit truncates codes modulo
+// 256, so that e.g. 300 silently becomes 44. NormalizeCode makes this
+// explicit while ensuring that a failure never becomes a success:
+//
+//   - codes within 0-255 are returned as is
+//   - negative codes produce CodeErr
+//   - codes above 255 are taken modulo 256, results of 0 produce CodeErr
+func NormalizeCode(code int) int {
+	switch {
+	case code < 0:
+		return CodeErr
+	case code > 255:
+		if code %= 256; code == 0 {
+			return CodeErr
+		}
+		return code
+	default:
+		return code
+	}
+}
+
+// SetDefaultErrorCode sets the exit code that Code returns for non-nil errors
+// which are not matched by any error handler or builtin rule. The default is
+// CodeErr. Explicit codes of ExitError values, flag.ErrHelp and nil errors are
+// not affected.
+//
+// Calling SetDefaultErrorCode is not goroutine-safe. Should be called early in
+// main.
+func SetDefaultErrorCode(code int) {
+	defaultErrorCode = code
+}
+
+// DefaultErrorCode returns the exit code that Code returns for errors which
+// are not matched by any error handler or builtin rule.
+func DefaultErrorCode() int {
+	return defaultErrorCode
+}
+
+// ResetDefaultErrorCode restores the default exit code for unmatched errors
+// to CodeErr.
+//
+// Calling ResetDefaultErrorCode is not goroutine-safe.
+func ResetDefaultErrorCode() {
+	defaultErrorCode = CodeErr
+}
+
+// SetHelpExitCode sets the exit code that Code returns for errors containing
+// flag.ErrHelp. The default is CodeHelpErr. This allows to treat invoking a
+// command with -help as success by passing CodeOK. No other rules are
+// affected.
+//
+// Calling SetHelpExitCode is not goroutine-safe. Should be called early in
+// main.
+func SetHelpExitCode(code int) {
+	helpExitCode = code
+}
+
+// HelpExitCode returns the exit code that Code returns for errors containing
+// flag.ErrHelp.
+func HelpExitCode() int {
+	return helpExitCode
+}
+
+// ResetHelpExitCode restores the exit code for errors containing flag.ErrHelp
+// to CodeHelpErr.
+//
+// Calling ResetHelpExitCode is not goroutine-safe.
+func ResetHelpExitCode() {
+	helpExitCode = CodeHelpErr
+}
+
+// CodeExtractorFunc extracts an exit code from err. If err carries an exit
+// code it should signal this by setting the second return value to true.
+type CodeExtractorFunc func(err error) (code int, ok bool)
+
+// RegisterCodeExtractor registers fn to extract exit codes from errors which
+// do not implement ExitError, e.g. errors of other libraries exposing their
+// exit code via a method with a different name:
+//
+//   exit.RegisterCodeExtractor(func(err error) (int, bool) {
+//     var statuser interface{ Status() int }
+//     if errors.As(err, &statuser) {
+//       return statuser.Status(), true
+//     }
+//     return 0, false
+//   })
+//
+// Extractors are only consulted by Code if err does not contain an ExitError.
+//
+// Calling RegisterCodeExtractor is not goroutine-safe. Should be called early
+// in main.
+func RegisterCodeExtractor(fn CodeExtractorFunc) {
+	codeExtractors = append(codeExtractors, fn)
+}
+
+// FinalCodeHookFunc receives the error passed to Exit and the exit code that
+// is about to be used and returns the exit code to actually exit with.
+type FinalCodeHookFunc func(err error, code int) int
+
+// SetFinalCodeHook sets a hook that is invoked by Exit right before exiting
+// and by RunMain before returning the exit code. It is the very last point
+// where the exit code can be observed or changed, e.g. for environment
+// specific fixups. Passing nil removes the hook.
+//
+// Calling SetFinalCodeHook is not goroutine-safe. Should be called early in
+// main.
+func SetFinalCodeHook(fn FinalCodeHookFunc) {
+	finalCodeHook = fn
+}
+
+// ExitLogFunc receives the error passed to Exit and the exit code that is
+// about to be used, e.g. for logging them.
+type ExitLogFunc func(err error, code int)
+
+// SetExitLogFunc sets a function that is invoked by Exit with the error and
+// the final exit code before anything else is printed, sent or run. See
+// SlogHandler for a ready-made implementation based on log/slog. Passing nil
+// removes the function, which is the default.
+//
+// Calling SetExitLogFunc is not goroutine-safe. Should be called early in
+// main.
+func SetExitLogFunc(fn ExitLogFunc) {
+	exitLogFunc = fn
+}
+
+// SetExitDelay configures Exit to sleep for d right before exiting, after
+// everything else was printed, sent and run. This gives asynchronous log
+// shippers time to flush the final messages in supervised environments. A
+// zero or negative d disables the delay, which is the default.
+//
+// Calling SetExitDelay is not goroutine-safe. Should be called early in main.
+func SetExitDelay(d time.Duration) {
+	exitDelay = d
+}
+
+// SetExitFunc replaces os.Exit as the function called with the exit code by
+// Exit and all other functions of this package that exit the program. This is
+// mainly useful in tests which need to observe the exit code without
+// terminating the test binary, see package exittest. Passing nil restores
+// os.Exit, which is the default. Returns the previously set function, so that
+// callers can restore it:
+//
+//   prev := exit.SetExitFunc(func(code int) { ... })
+//   defer exit.SetExitFunc(prev)
+//
+// Unlike ExitWith and WithExitFunc, SetExitFunc affects all exits. Calling
+// SetExitFunc is not goroutine-safe.
+func SetExitFunc(fn func(int)) func(int) {
+	if fn == nil {
+		fn = os.Exit
+	}
+
+	prev := osExit
+	osExit = fn
+
+	return prev
 }
 
 // Exit is a convenience alternative for os.Exit. Calls os.Exit with the exit
 // code obtained from err. If err is nil this is equivalent to os.Exit(0).
 //
+// If a hook was set via SetFinalCodeHook, it may change the exit code before
+// exiting. If set via SetExitLogFunc, the error and exit code are passed to a
+// log function. If enabled via SetExitBanner, a banner describing the exit
+// code is printed to stderr before exiting with a non-zero code. If
+// configured via SetExitSocket, an exit event is sent to a Unix socket. Then
+// all functions registered via OnExit are run. Finally, Exit sleeps for the
+// delay configured via SetExitDelay, if any.
+//
+// The behavior of a single call can be adjusted via opts without modifying
+// global state, e.g.:
+//
+//   exit.Exit(err, exit.WithWriter(os.Stderr), exit.WithClamp(true))
+//
 // See Code for possible exit codes.
-func Exit(err error) {
-	osExit(Code(err))
+func Exit(err error, opts ...Option) {
+	cfg := newExitConfig(opts...)
+
+	var printed bool
+	if cfg.writer != nil {
+		printed = printError(cfg.writer, err)
+	}
+
+	exit(err, cfg.code(err), cfg.exitFn, printed)
+}
+
+// ExitWith is like Exit but calls exitFn with the exit code obtained from err
+// instead of os.Exit. This is useful to flush buffers or run additional cleanup
+// before calling os.Exit in embedded scenarios, or in tests. exitFn is always
+// called, even if the exit code is 0.
+//
+// ExitWith is equivalent to calling Exit with WithExitFunc(exitFn).
+//
+// See Exit for more information.
+func ExitWith(err error, exitFn func(int)) {
+	Exit(err, WithExitFunc(exitFn))
+}
+
+// ExitMapped is like Exit but translates the exit code obtained from err via
+// table before exiting. Codes not present in table are used as is. If err is
+// nil this is equivalent to os.Exit(0) regardless of table.
+//
+// Unlike SetErrorHandler, ExitMapped does not mutate global state.
+func ExitMapped(err error, table map[int]int) {
+	code := Code(err)
+
+	if mapped, ok := table[code]; ok && err != nil {
+		code = mapped
+	}
+
+	exit(err, code, osExit, false)
+}
+
+// Fatal is like Exit but first writes the message of err followed by a
+// newline to stderr, so that users see why the program failed. Nothing is
+// printed if err is nil or its message is empty. The destination can be
+// changed via SetErrorWriter.
+//
+// See Exit for more information.
+func Fatal(err error) {
+	Exit(err, WithWriter(errWriter))
+}
+
+// Exitf creates a new error with given exit code via Errorf, prints its
+// message to stderr and exits. It is a shorthand for:
+//
+//   exit.Fatal(exit.Errorf(code, format, args...))
+//
+// Exitf always exits. See Fatal for more information.
+func Exitf(code int, format string, args ...interface{}) {
+	Fatal(Errorf(code, format, args...))
+}
+
+// printError writes the message of err followed by a newline to w if err is
+// non-nil and its message is not empty. Reports whether anything was written,
+// which is not the case if printing was suppressed via SetErrorWriter(nil).
+func printError(w io.Writer, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if sw, ok := w.(*syncWriter); ok && !sw.enabled() {
+		return false
+	}
+
+	msg := err.Error()
+	if msg == "" {
+		return false
+	}
+
+	fmt.Fprintln(w, msg)
+
+	return true
+}
+
+// exit calls exitFn with code after running the final code hook and the log
+// function, printing the banner, sending the exit event, running the functions
+// registered via OnExit and sleeping for the exit delay. printed reports
+// whether the caller already printed the message of err.
+func exit(err error, code int, exitFn func(int), printed bool) {
+	if finalCodeHook != nil {
+		code = finalCodeHook(err, code)
+	}
+
+	if exitLogFunc != nil {
+		exitLogFunc(err, code)
+	}
+
+	bannerPrinted := printBanner(code)
+	lastExitPrinted = printed || bannerPrinted
+	sendExitEvent(err, code)
+	runOnExit()
+
+	if exitDelay > 0 {
+		sleep(exitDelay)
+	}
+
+	exitFn(code)
 }
@@ -1,13 +1,21 @@
 package exit
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strconv"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 )
 
 var errUntyped = errors.New("error")
@@ -16,6 +24,38 @@ func wrapErr(err error) error {
 	return fmt.Errorf("wrapped: %w", err)
 }
 
+// captureExit replaces osExit for the duration of the test. The returned
+// pointer receives the code osExit was called with. It is set to -1 if osExit
+// was not called.
+func captureExit(t *testing.T) *int {
+	code := -1
+
+	osExit = func(c int) { code = c }
+	t.Cleanup(func() { osExit = os.Exit })
+
+	return &code
+}
+
+// captureOutput replaces errWriter for the duration of the test and returns
+// the buffer that receives the output.
+func captureOutput(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	errWriter = &buf
+	t.Cleanup(func() { errWriter = defaultErrWriter })
+
+	return &buf
+}
+
+// fakeNetError implements net.Error.
+type fakeNetError struct {
+	timeout bool
+}
+
+func (e *fakeNetError) Error() string   { return "network error" }
+func (e *fakeNetError) Timeout() bool   { return e.timeout }
+func (e *fakeNetError) Temporary() bool { return e.timeout }
+
 func TestExit(t *testing.T) {
 	for _, testCase := range []struct {
 		name string
@@ -31,6 +71,25 @@ func TestExit(t *testing.T) {
 		{name: "wrapped flag.Help", err: wrapErr(flag.ErrHelp), code: CodeHelpErr},
 		{name: "exec.ExitError", err: execExitError(10), code: 10},
 		{name: "wrapped exec.ExitError", err: wrapErr(execExitError(3)), code: 3},
+		{name: "context.DeadlineExceeded", err: context.DeadlineExceeded, code: CodeTempFail},
+		{name: "wrapped context.DeadlineExceeded", err: wrapErr(context.DeadlineExceeded), code: CodeTempFail},
+		{name: "context.Canceled", err: context.Canceled, code: CodeInterrupt},
+		{name: "wrapped context.Canceled", err: wrapErr(context.Canceled), code: CodeInterrupt},
+		{name: "ExitError wrapping context.Canceled", err: Error(CodeIOErr, context.Canceled), code: CodeIOErr},
+		{name: "os.ErrNotExist", err: os.ErrNotExist, code: CodeNoInput},
+		{name: "wrapped os.ErrNotExist", err: wrapErr(&os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}), code: CodeNoInput},
+		{name: "os.PathError with errno", err: &os.PathError{Op: "open", Path: "foo", Err: syscall.ENOENT}, code: CodeNoInput},
+		{name: "net.OpError with errno", err: &net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}, code: CodeUnavailable},
+		{name: "os.ErrPermission", err: os.ErrPermission, code: CodeNoPerm},
+		{name: "wrapped os.ErrPermission", err: wrapErr(os.ErrPermission), code: CodeNoPerm},
+		{name: "os.ErrExist", err: os.ErrExist, code: CodeCantCreat},
+		{name: "wrapped os.ErrExist", err: wrapErr(os.ErrExist), code: CodeCantCreat},
+		{name: "ExitError wrapping os.ErrNotExist", err: Error(CodeConfig, os.ErrNotExist), code: CodeConfig},
+		{name: "net.Error timeout", err: &fakeNetError{timeout: true}, code: CodeTempFail},
+		{name: "wrapped net.Error timeout", err: wrapErr(&fakeNetError{timeout: true}), code: CodeTempFail},
+		{name: "net.Error", err: &fakeNetError{}, code: CodeUnavailable},
+		{name: "wrapped net.Error", err: wrapErr(&fakeNetError{}), code: CodeUnavailable},
+		{name: "ExitError wrapping net.Error", err: Error(CodeProtocol, &fakeNetError{}), code: CodeProtocol},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			var got int
@@ -47,6 +106,82 @@ func TestExit(t *testing.T) {
 	}
 }
 
+func TestExitWith(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+		{name: "ExitError", err: wrapErr(Error(CodeNoPerm, errUntyped)), code: CodeNoPerm},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			osExit = func(code int) { t.Errorf("unexpected call to osExit with code %d", code) }
+			defer func() { osExit = os.Exit }()
+
+			var calls []int
+
+			ExitWith(testCase.err, func(code int) { calls = append(calls, code) })
+
+			if len(calls) != 1 || calls[0] != testCase.code {
+				t.Errorf("got calls %v, want [%d]", calls, testCase.code)
+			}
+		})
+	}
+}
+
+func TestExitMapped(t *testing.T) {
+	table := map[int]int{
+		CodeOK:    CodeErr,
+		CodeIOErr: 80,
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "mapped code", err: wrapErr(Error(CodeIOErr, errUntyped)), code: 80},
+		{name: "unmapped code", err: Error(CodeNoPerm, errUntyped), code: CodeNoPerm},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			ExitMapped(testCase.err, table)
+
+			if *code != testCase.code {
+				t.Errorf("got %d, want %d", *code, testCase.code)
+			}
+		})
+	}
+}
+
+func TestExplicitCode(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+		ok   bool
+	}{
+		{name: "no error"},
+		{name: "untyped error", err: errUntyped},
+		{name: "flag.ErrHelp", err: flag.ErrHelp},
+		{name: "os.ErrNotExist", err: os.ErrNotExist},
+		{name: "ExitError", err: Error(CodeIOErr, errUntyped), code: CodeIOErr, ok: true},
+		{name: "wrapped ExitError", err: wrapErr(Error(CodeErr, errUntyped)), code: CodeErr, ok: true},
+		{name: "exec.ExitError", err: execExitError(3), code: 3, ok: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, ok := ExplicitCode(testCase.err)
+			if code != testCase.code || ok != testCase.ok {
+				t.Errorf("got (%d, %t), want (%d, %t)", code, ok, testCase.code, testCase.ok)
+			}
+		})
+	}
+}
+
 func TestError(t *testing.T) {
 	err := Error(CodeOSErr, nil)
 	if err != nil {
@@ -71,6 +206,48 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestWithCode(t *testing.T) {
+	if err := WithCode(CodeIOErr, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	if code := Code(WithCode(CodeIOErr, errUntyped)); code != CodeIOErr {
+		t.Errorf("uncoded error: got code %d, want %d", code, CodeIOErr)
+	}
+
+	inner := wrapErr(execExitError(2))
+
+	err := WithCode(CodeUnavailable, inner)
+	if err != inner {
+		t.Errorf("coded error: got %#v, want %#v", err, inner)
+	}
+
+	if code := Code(err); code != 2 {
+		t.Errorf("coded error: got code %d, want %d", code, 2)
+	}
+}
+
+func TestOverrideCode(t *testing.T) {
+	if err := OverrideCode(CodeUnavailable, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	inner := execExitError(2)
+
+	err := OverrideCode(CodeUnavailable, inner)
+	if code := Code(err); code != CodeUnavailable {
+		t.Errorf("got code %d, want %d", code, CodeUnavailable)
+	}
+
+	if code := Code(wrapErr(OverrideCode(CodeConfig, Error(CodeIOErr, errUntyped)))); code != CodeConfig {
+		t.Errorf("got code %d, want %d", code, CodeConfig)
+	}
+
+	if wrappedErr := errors.Unwrap(err); wrappedErr != inner {
+		t.Errorf("errors.Unwrap(err), got: %#v, want: %#v", wrappedErr, inner)
+	}
+}
+
 func TestErrorf(t *testing.T) {
 	err := Errorf(CodeOSErr, "error: %s", "some-arg")
 	if exitErr, ok := err.(ExitError); !ok {
@@ -102,12 +279,106 @@ func TestErrorp(t *testing.T) {
 	}
 }
 
+func TestErrorpf(t *testing.T) {
+	fn := func(ret error) (err error) {
+		defer Errorpf(CodeIOErr, &err, "writing %s (attempt %d)", "foo.txt", 2)
+		return ret
+	}
+
+	if err := fn(nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	err := fn(errUntyped)
+	if err == nil {
+		t.Fatal("got nil, want ExitError")
+	}
+
+	if msg, want := err.Error(), "writing foo.txt (attempt 2): error"; msg != want {
+		t.Errorf("got message %q, want %q", msg, want)
+	}
+
+	if code := Code(err); code != CodeIOErr {
+		t.Errorf("got code %d, want %d", code, CodeIOErr)
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Error("expected errors.Is to find the original error")
+	}
+}
+
+func TestCaptureCode(t *testing.T) {
+	var code int
+
+	fn := func(ret error) (err error) {
+		defer CaptureCode(&err, &code)
+		err = errUntyped
+		return ret
+	}
+
+	if err := fn(Error(CodeUsage, errUntyped)); Code(err) != CodeUsage {
+		t.Errorf("got error %#v, want ExitError with code %d", err, CodeUsage)
+	}
+
+	if code != CodeUsage {
+		t.Errorf("got code %d, want %d", code, CodeUsage)
+	}
+
+	fn(nil)
+
+	if code != CodeOK {
+		t.Errorf("got code %d, want %d", code, CodeOK)
+	}
+}
+
+func TestContextError(t *testing.T) {
+	if err := ContextError(nil, "context"); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	origErr := execExitError(10)
+
+	err := ContextError(origErr, "running %s", "cmd")
+	if exitErr, ok := err.(ExitError); !ok {
+		t.Errorf("got %#v, want ExitError", err)
+	} else if code := exitErr.ExitCode(); code != 10 {
+		t.Errorf("got ExitError with code %d, want %d", code, 10)
+	}
+
+	if want := "running cmd: exit status 10"; err.Error() != want {
+		t.Errorf("got msg %q, want %q", err.Error(), want)
+	}
+
+	if wrappedErr := errors.Unwrap(err); wrappedErr != origErr {
+		t.Errorf("errors.Unwrap(err), got: %#v, want: %#v", wrappedErr, origErr)
+	}
+
+	err = ContextError(errUntyped, "context")
+	if _, ok := err.(ExitError); ok {
+		t.Errorf("got ExitError %#v for uncoded inner error", err)
+	}
+
+	if code := Code(err); code != CodeErr {
+		t.Errorf("got code %d, want %d", code, CodeErr)
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Errorf("errors.Is(%#v, %#v) returned false", err, errUntyped)
+	}
+}
+
 func TestSetErrorHandler(t *testing.T) {
 	SetErrorHandler(func(err error) (code int, handled bool) {
 		if err == nil {
 			t.Error("error handler called with nil error")
 		}
 
+		var netErr *fakeNetError
+
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrNotExist) || errors.As(err, &netErr) {
+			return CodeSoftware, true
+		}
+
 		var exitErr ExitError
 
 		if errors.As(err, &exitErr) {
@@ -133,6 +404,256 @@ func TestSetErrorHandler(t *testing.T) {
 		{name: "wrapped flag.Help", err: wrapErr(flag.ErrHelp), code: CodeHelpErr},
 		{name: "exec.ExitError", err: execExitError(10), code: 11},
 		{name: "wrapped exec.ExitError", err: wrapErr(execExitError(3)), code: 4},
+		{name: "context.Canceled", err: context.Canceled, code: CodeInterrupt},
+		{name: "wrapped context.DeadlineExceeded", err: wrapErr(context.DeadlineExceeded), code: CodeSoftware},
+		{name: "wrapped os.ErrNotExist", err: wrapErr(os.ErrNotExist), code: CodeSoftware},
+		{name: "os.ErrPermission", err: os.ErrPermission, code: CodeNoPerm},
+		{name: "wrapped net.Error timeout", err: wrapErr(&fakeNetError{timeout: true}), code: CodeSoftware},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := Code(testCase.err); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestSetFinalCodeHook(t *testing.T) {
+	code := captureExit(t)
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if *code != CodeIOErr {
+		t.Errorf("without hook: got %d, want %d", *code, CodeIOErr)
+	}
+
+	var hookErr error
+
+	SetFinalCodeHook(func(err error, code int) int {
+		hookErr = err
+		if code == CodeIOErr {
+			return CodeTempFail
+		}
+		return code
+	})
+	defer SetFinalCodeHook(nil)
+
+	err := Error(CodeIOErr, errUntyped)
+
+	Exit(err)
+
+	if *code != CodeTempFail {
+		t.Errorf("with hook: got %d, want %d", *code, CodeTempFail)
+	}
+
+	if hookErr != err {
+		t.Errorf("hook got err %#v, want %#v", hookErr, err)
+	}
+
+	Exit(nil)
+
+	if *code != CodeOK {
+		t.Errorf("with hook and nil error: got %d, want %d", *code, CodeOK)
+	}
+}
+
+func TestSetExitDelay(t *testing.T) {
+	var events []string
+
+	osExit = func(code int) { events = append(events, "exit") }
+	sleep = func(d time.Duration) { events = append(events, "sleep "+d.String()) }
+	defer func() {
+		osExit = os.Exit
+		sleep = time.Sleep
+	}()
+
+	Exit(errUntyped)
+
+	if len(events) != 1 || events[0] != "exit" {
+		t.Errorf("without delay: got events %v, want [exit]", events)
+	}
+
+	SetExitDelay(2 * time.Second)
+	defer SetExitDelay(0)
+
+	events = nil
+
+	Exit(errUntyped)
+
+	if len(events) != 2 || events[0] != "sleep 2s" || events[1] != "exit" {
+		t.Errorf("with delay: got events %v, want [sleep 2s exit]", events)
+	}
+}
+
+type statusError struct{ status int }
+
+func (e statusError) Error() string { return "status error" }
+func (e statusError) Status() int   { return e.status }
+
+func TestRegisterCodeExtractor(t *testing.T) {
+	if code := Code(statusError{CodeIOErr}); code != CodeErr {
+		t.Errorf("without extractor: got %d, want %d", code, CodeErr)
+	}
+
+	RegisterCodeExtractor(func(err error) (int, bool) {
+		var statuser interface{ Status() int }
+		if errors.As(err, &statuser) {
+			return statuser.Status(), true
+		}
+		return 0, false
+	})
+	defer func() { codeExtractors = nil }()
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+		{name: "Status() int", err: statusError{CodeIOErr}, code: CodeIOErr},
+		{name: "wrapped Status() int", err: wrapErr(statusError{CodeNoPerm}), code: CodeNoPerm},
+		{name: "ExitError takes precedence", err: Error(CodeConfig, statusError{CodeIOErr}), code: CodeConfig},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := Code(testCase.err); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestAddErrorHandler(t *testing.T) {
+	var calls []string
+
+	AddErrorHandler(func(err error) (code int, handled bool) {
+		calls = append(calls, "first")
+		if errors.Is(err, flag.ErrHelp) {
+			return CodeOK, true
+		}
+		return 0, false
+	})
+	AddErrorHandler(nil)
+	AddErrorHandler(func(err error) (code int, handled bool) {
+		calls = append(calls, "second")
+		if errors.Is(err, flag.ErrHelp) || errors.Is(err, errUntyped) {
+			return CodeUsage, true
+		}
+		return 0, false
+	})
+	defer ClearErrorHandlers()
+
+	for _, testCase := range []struct {
+		name  string
+		err   error
+		code  int
+		calls []string
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "first handler wins", err: flag.ErrHelp, code: CodeOK, calls: []string{"first"}},
+		{name: "second handler", err: wrapErr(errUntyped), code: CodeUsage, calls: []string{"first", "second"}},
+		{name: "not handled", err: Error(CodeIOErr, errors.New("foo")), code: CodeIOErr, calls: []string{"first", "second"}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			calls = nil
+
+			if got := Code(testCase.err); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+
+			if !reflect.DeepEqual(calls, testCase.calls) {
+				t.Errorf("got calls %v, want %v", calls, testCase.calls)
+			}
+		})
+	}
+
+	SetErrorHandler(func(err error) (code int, handled bool) {
+		return CodeConfig, true
+	})
+
+	if got := Code(flag.ErrHelp); got != CodeConfig {
+		t.Errorf("SetErrorHandler did not replace handlers: got %d, want %d", got, CodeConfig)
+	}
+
+	ClearErrorHandlers()
+
+	if got := Code(flag.ErrHelp); got != CodeHelpErr {
+		t.Errorf("ClearErrorHandlers did not remove handlers: got %d, want %d", got, CodeHelpErr)
+	}
+}
+
+func TestSetErrorHandler_Concurrent(t *testing.T) {
+	defer ClearErrorHandlers()
+
+	handler := func(err error) (code int, handled bool) {
+		return CodeUsage, true
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			SetErrorHandler(handler)
+		}()
+
+		go func() {
+			defer wg.Done()
+			AddErrorHandler(handler)
+		}()
+
+		go func() {
+			defer wg.Done()
+			if code := Code(errUntyped); code != CodeUsage && code != CodeErr {
+				t.Errorf("got unexpected code %d", code)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestNormalizeCode(t *testing.T) {
+	for _, testCase := range []struct {
+		code int
+		want int
+	}{
+		{code: 0, want: 0},
+		{code: 1, want: 1},
+		{code: 255, want: 255},
+		{code: 256, want: CodeErr},
+		{code: 300, want: 44},
+		{code: 512, want: CodeErr},
+		{code: -1, want: CodeErr},
+		{code: -300, want: CodeErr},
+	} {
+		if got := NormalizeCode(testCase.code); got != testCase.want {
+			t.Errorf("NormalizeCode(%d): got %d, want %d", testCase.code, got, testCase.want)
+		}
+	}
+}
+
+func TestClampCodes(t *testing.T) {
+	err := Error(300, errUntyped)
+
+	if code := Code(err); code != 300 {
+		t.Errorf("disabled: got %d, want %d", code, 300)
+	}
+
+	ClampCodes(true)
+	defer ClampCodes(false)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "boundary 255", err: Error(255, errUntyped), code: 255},
+		{name: "above 255", err: wrapErr(err), code: 44},
+		{name: "negative", err: Error(-1, errUntyped), code: CodeErr},
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			if got := Code(testCase.err); got != testCase.code {
@@ -174,3 +695,437 @@ func execExitError(code int) error {
 	cmd.Env = []string{"GO_PROCESS_EXIT_CODE_HELPER=1"}
 	return cmd.Run()
 }
+
+func TestFatal(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		err    error
+		code   int
+		output string
+	}{
+		{name: "nil", code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr, output: "error\n"},
+		{name: "ExitError", err: Errorf(CodeIOErr, "disk on fire"), code: CodeIOErr, output: "disk on fire\n"},
+		{name: "empty message", err: Errorf(CodeUsage, ""), code: CodeUsage},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+			buf := captureOutput(t)
+
+			Fatal(testCase.err)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+
+			if output := buf.String(); output != testCase.output {
+				t.Errorf("expected output %q, got %q", testCase.output, output)
+			}
+		})
+	}
+}
+
+func TestSetDefaultErrorCode(t *testing.T) {
+	SetDefaultErrorCode(CodeSoftware)
+	t.Cleanup(ResetDefaultErrorCode)
+
+	if code := DefaultErrorCode(); code != CodeSoftware {
+		t.Fatalf("expected default error code %d, got %d", CodeSoftware, code)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeSoftware},
+		{name: "wrapped untyped error", err: wrapErr(errUntyped), code: CodeSoftware},
+		{name: "flag.ErrHelp", err: flag.ErrHelp, code: CodeHelpErr},
+		{name: "ExitError", err: Error(CodeErr, errUntyped), code: CodeErr},
+		{name: "wrapped ExitError", err: wrapErr(Error(CodeIOErr, errUntyped)), code: CodeIOErr},
+		{name: "builtin rule", err: wrapErr(os.ErrNotExist), code: CodeNoInput},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+
+	ResetDefaultErrorCode()
+
+	if code := Code(errUntyped); code != CodeErr {
+		t.Errorf("expected code %d after reset, got %d", CodeErr, code)
+	}
+}
+
+func TestExitf(t *testing.T) {
+	code := captureExit(t)
+	buf := captureOutput(t)
+
+	Exitf(CodeConfig, "invalid value %q for %s", "foo", "bar")
+
+	if *code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, *code)
+	}
+
+	if output, want := buf.String(), "invalid value \"foo\" for bar\n"; output != want {
+		t.Errorf("expected output %q, got %q", want, output)
+	}
+}
+
+func TestSetExitLogFunc(t *testing.T) {
+	var (
+		gotErr  error
+		gotCode int
+	)
+
+	SetExitLogFunc(func(err error, code int) { gotErr, gotCode = err, code })
+	defer SetExitLogFunc(nil)
+
+	SetFinalCodeHook(func(err error, code int) int { return code + 1 })
+	defer SetFinalCodeHook(nil)
+
+	captureExit(t)
+
+	err := Error(CodeIOErr, errUntyped)
+
+	Exit(err)
+
+	if gotErr != err || gotCode != CodeIOErr+1 {
+		t.Errorf("expected log func to receive (%v, %d), got (%v, %d)", err, CodeIOErr+1, gotErr, gotCode)
+	}
+}
+
+func TestDefaultHandler(t *testing.T) {
+	for _, err := range []error{
+		nil,
+		errUntyped,
+		flag.ErrHelp,
+		wrapErr(flag.ErrHelp),
+		Error(CodeIOErr, errUntyped),
+		wrapErr(Error(CodeNoPerm, errUntyped)),
+		execExitError(3),
+		context.Canceled,
+		wrapErr(os.ErrNotExist),
+	} {
+		code, handled := DefaultHandler(err)
+		if want := err != errUntyped; handled != want {
+			t.Errorf("expected handled=%t for %v, got %t", want, err, handled)
+		}
+
+		if want := Code(err); code != want {
+			t.Errorf("expected code %d for %v, got %d", want, err, code)
+		}
+	}
+}
+
+func TestDefaultHandler_Delegation(t *testing.T) {
+	var decisions []int
+
+	SetErrorHandler(func(err error) (int, bool) {
+		code, handled := DefaultHandler(err)
+		decisions = append(decisions, code)
+		return code, handled
+	})
+	defer SetErrorHandler(nil)
+
+	if code := Code(wrapErr(Error(CodeIOErr, errUntyped))); code != CodeIOErr {
+		t.Errorf("expected code %d, got %d", CodeIOErr, code)
+	}
+
+	if len(decisions) != 1 || decisions[0] != CodeIOErr {
+		t.Errorf("expected a single logged decision, got %v", decisions)
+	}
+}
+
+// pointerError is an error whose methods can be called on nil pointers.
+type pointerError struct{}
+
+func (*pointerError) Error() string { return "pointer error" }
+
+func TestDetectTypedNil(t *testing.T) {
+	var typedNil *pointerError
+
+	if code := Code(typedNil); code == CodeOK {
+		t.Fatalf("expected non-zero code for typed nil with detection disabled, got %d", code)
+	}
+
+	DetectTypedNil(true)
+	defer DetectTypedNil(false)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "nil", code: CodeOK},
+		{name: "typed nil pointer", err: typedNil, code: CodeOK},
+		{name: "typed nil slice", err: multiError(nil), code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+		{name: "ExitError", err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestTag(t *testing.T) {
+	if err := Tag(CodeIOErr, nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	original := &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}
+	tagged := Tag(CodeConfig, original)
+
+	if !errors.Is(tagged, original) {
+		t.Error("expected errors.Is to find the original error")
+	}
+
+	if !errors.Is(tagged, os.ErrNotExist) {
+		t.Error("expected errors.Is to find errors wrapped by the original error")
+	}
+
+	var pathErr *os.PathError
+	if !errors.As(tagged, &pathErr) || pathErr != original {
+		t.Error("expected errors.As to find the original error")
+	}
+
+	if msg := tagged.Error(); msg != original.Error() {
+		t.Errorf("expected message %q, got %q", original.Error(), msg)
+	}
+
+	if code := Code(tagged); code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, code)
+	}
+
+	if code := Code(wrapErr(Tag(CodeUsage, Error(CodeIOErr, errUntyped)))); code != CodeUsage {
+		t.Errorf("expected tag to override inner code, got %d", code)
+	}
+}
+
+func TestSetHelpExitCode(t *testing.T) {
+	defer ResetHelpExitCode()
+
+	for _, testCase := range []struct {
+		name string
+		code int
+	}{
+		{name: "default", code: CodeHelpErr},
+		{name: "success", code: CodeOK},
+		{name: "custom", code: CodeUsage},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if testCase.code != CodeHelpErr {
+				SetHelpExitCode(testCase.code)
+				defer ResetHelpExitCode()
+			}
+
+			if code := HelpExitCode(); code != testCase.code {
+				t.Errorf("expected help exit code %d, got %d", testCase.code, code)
+			}
+
+			if code := Code(flag.ErrHelp); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+
+			if code := Code(wrapErr(flag.ErrHelp)); code != testCase.code {
+				t.Errorf("expected code %d for wrapped flag.ErrHelp, got %d", testCase.code, code)
+			}
+
+			if code := Code(errUntyped); code != CodeErr {
+				t.Errorf("expected code %d for other errors, got %d", CodeErr, code)
+			}
+
+			if code := Code(Error(CodeHelpErr, errUntyped)); code != CodeHelpErr {
+				t.Errorf("expected explicit code %d to be unaffected, got %d", CodeHelpErr, code)
+			}
+		})
+	}
+
+	if code := HelpExitCode(); code != CodeHelpErr {
+		t.Errorf("expected help exit code %d after reset, got %d", CodeHelpErr, code)
+	}
+}
+
+// codeError only implements a Code() int method.
+type codeError struct{ code int }
+
+func (e codeError) Error() string { return "code error" }
+func (e codeError) Code() int     { return e.code }
+
+// bothCodesError implements both ExitError and a Code() int method.
+type bothCodesError struct{}
+
+func (bothCodesError) Error() string { return "both codes error" }
+func (bothCodesError) Code() int     { return CodeDataErr }
+func (bothCodesError) ExitCode() int { return CodeIOErr }
+
+func TestCode_CodeMethod(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "Code() int", err: codeError{CodeNoHost}, code: CodeNoHost},
+		{name: "wrapped Code() int", err: wrapErr(codeError{CodeNoHost}), code: CodeNoHost},
+		{name: "ExitError takes precedence over Code() int", err: bothCodesError{}, code: CodeIOErr},
+		{name: "outer ExitError with inner Code() int", err: Error(CodeUsage, codeError{CodeNoHost}), code: CodeUsage},
+		{name: "flag.ErrHelp takes precedence", err: fmt.Errorf("%w: %v", flag.ErrHelp, codeError{CodeNoHost}), code: CodeHelpErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestCode_PathErrorOp(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "open unclassified", err: &os.PathError{Op: "open", Path: "foo", Err: errUntyped}, code: CodeNoInput},
+		{name: "open not exist", err: &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}, code: CodeNoInput},
+		{name: "open exist", err: &os.PathError{Op: "open", Path: "foo", Err: os.ErrExist}, code: CodeCantCreat},
+		{name: "create", err: &os.PathError{Op: "create", Path: "foo", Err: errUntyped}, code: CodeCantCreat},
+		{name: "mkdir", err: &os.PathError{Op: "mkdir", Path: "foo", Err: errUntyped}, code: CodeCantCreat},
+		{name: "mkdir parent not exist", err: &os.PathError{Op: "mkdir", Path: "foo/bar", Err: os.ErrNotExist}, code: CodeCantCreat},
+		{name: "mkdir exist", err: &os.PathError{Op: "mkdir", Path: "foo", Err: os.ErrExist}, code: CodeCantCreat},
+		{name: "mkdir permission", err: &os.PathError{Op: "mkdir", Path: "foo", Err: os.ErrPermission}, code: CodeNoPerm},
+		{name: "write", err: wrapErr(&os.PathError{Op: "write", Path: "foo", Err: errUntyped}), code: CodeIOErr},
+		{name: "open permission", err: &os.PathError{Op: "open", Path: "foo", Err: os.ErrPermission}, code: CodeNoPerm},
+		{name: "write permission", err: &os.PathError{Op: "write", Path: "foo", Err: os.ErrPermission}, code: CodeNoPerm},
+		{name: "other op falls back to inner error", err: &os.PathError{Op: "stat", Path: "foo", Err: os.ErrNotExist}, code: CodeNoInput},
+		{name: "other op unclassified", err: &os.PathError{Op: "chmod", Path: "foo", Err: errUntyped}, code: CodeErr},
+		{name: "exit error wins", err: Error(CodeConfig, &os.PathError{Op: "mkdir", Path: "foo", Err: errUntyped}), code: CodeConfig},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestCode_PathErrorExclusiveCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "existing")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err == nil {
+		f.Close()
+		t.Fatal("expected exclusive create of existing file to fail")
+	}
+
+	if code := Code(err); code != CodeCantCreat {
+		t.Errorf("expected code %d, got %d for %v", CodeCantCreat, code, err)
+	}
+
+	err = os.Mkdir(path, 0o755)
+	if code := Code(err); code != CodeCantCreat {
+		t.Errorf("expected code %d, got %d for %v", CodeCantCreat, code, err)
+	}
+}
+
+func TestSetExitFunc(t *testing.T) {
+	t.Cleanup(func() { osExit = os.Exit })
+
+	code := -1
+
+	if prev := SetExitFunc(func(c int) { code = c }); reflect.ValueOf(prev).Pointer() != reflect.ValueOf(os.Exit).Pointer() {
+		t.Error("expected os.Exit to be returned as the previous func")
+	}
+
+	Exit(Error(CodeConfig, errUntyped))
+
+	if code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, code)
+	}
+
+	SetExitFunc(nil)
+
+	if reflect.ValueOf(osExit).Pointer() != reflect.ValueOf(os.Exit).Pointer() {
+		t.Error("expected nil to restore os.Exit")
+	}
+}
+
+func TestSetErrorHandler2(t *testing.T) {
+	errUnhandled := errors.New("unhandled")
+
+	SetErrorHandler2(func(err error, defaultCode int) (code int, handled bool) {
+		if err == nil {
+			t.Error("error handler called with nil error")
+		}
+
+		if errors.Is(err, errUnhandled) {
+			return 0, false
+		}
+
+		return defaultCode + 1, true
+	})
+	defer SetErrorHandler2(nil)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "nil", err: nil, code: CodeOK},
+		{name: "exit error", err: Error(CodeIOErr, errUntyped), code: CodeIOErr + 1},
+		{name: "builtin rule", err: wrapErr(os.ErrNotExist), code: CodeNoInput + 1},
+		{name: "fallback", err: errUntyped, code: CodeErr + 1},
+		{name: "unhandled", err: wrapErr(errUnhandled), code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestSetErrorHandler2_DefaultErrorCode(t *testing.T) {
+	SetDefaultErrorCode(CodeSoftware)
+	defer ResetDefaultErrorCode()
+
+	SetErrorHandler2(func(err error, defaultCode int) (int, bool) {
+		return defaultCode + 1, true
+	})
+	defer SetErrorHandler2(nil)
+
+	if code := Code(errUntyped); code != CodeSoftware+1 {
+		t.Errorf("expected code %d, got %d", CodeSoftware+1, code)
+	}
+}
+
+func TestSetErrorHandler2_ReplacesHandlers(t *testing.T) {
+	defer ClearErrorHandlers()
+
+	AddErrorHandler(func(err error) (int, bool) { return CodeUsage, true })
+
+	SetErrorHandler2(func(err error, defaultCode int) (int, bool) {
+		return defaultCode + 1, true
+	})
+
+	if code := Code(errUntyped); code != CodeErr+1 {
+		t.Errorf("expected SetErrorHandler2 to replace existing handlers, got code %d", code)
+	}
+
+	SetErrorHandler(func(err error) (int, bool) { return CodeConfig, true })
+
+	if code := Code(errUntyped); code != CodeConfig {
+		t.Errorf("expected SetErrorHandler to replace SetErrorHandler2, got code %d", code)
+	}
+}
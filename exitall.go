@@ -0,0 +1,21 @@
+//go:build go1.20
+// +build go1.20
+
+package exit
+
+import "errors"
+
+// ExitAll joins all non-nil errors in errs via errors.Join and exits with the
+// code determined for the joined error. If errs contains multiple errors
+// carrying an exit code, the highest code wins as described in Code. If all
+// errors are nil or errs is empty, the program exits with CodeOK.
+//
+// This is useful to make a single exit decision for errors collected during
+// shutdown:
+//
+//   exit.ExitAll(server.Shutdown(ctx), db.Close(), logger.Sync())
+//
+// See Exit for more information.
+func ExitAll(errs ...error) {
+	Exit(errors.Join(errs...))
+}
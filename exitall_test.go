@@ -0,0 +1,35 @@
+//go:build go1.20
+// +build go1.20
+
+package exit
+
+import "testing"
+
+func TestExitAll(t *testing.T) {
+	ioErr := Error(CodeIOErr, errUntyped)
+	permErr := Error(CodeNoPerm, errUntyped)
+
+	for _, testCase := range []struct {
+		name string
+		errs []error
+		code int
+	}{
+		{name: "no errors", code: CodeOK},
+		{name: "all nil", errs: []error{nil, nil}, code: CodeOK},
+		{name: "single error", errs: []error{nil, ioErr, nil}, code: CodeIOErr},
+		{name: "single uncoded error", errs: []error{errUntyped}, code: CodeErr},
+		{name: "multiple errors", errs: []error{ioErr, nil, permErr}, code: CodeNoPerm},
+		{name: "multiple errors reversed", errs: []error{permErr, ioErr}, code: CodeNoPerm},
+		{name: "coded and uncoded", errs: []error{errUntyped, ioErr}, code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			ExitAll(testCase.errs...)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+		})
+	}
+}
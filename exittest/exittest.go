@@ -0,0 +1,81 @@
+// Package exittest provides helpers for testing the exit codes produced by
+// package exit.
+//
+// Example:
+//
+//   func TestRun(t *testing.T) {
+//     exittest.AssertCode(t, exit.CodeConfig, run([]string{"-config", "missing.yaml"}))
+//
+//     exittest.AssertExit(t, exit.CodeUsage, func() {
+//       exit.Exit(run([]string{"-unknown-flag"}))
+//     })
+//   }
+package exittest
+
+import (
+	"testing"
+
+	"github.com/martinohmann/exit"
+)
+
+// AssertCode fails the test if the exit code computed for err via exit.Code
+// is not want.
+func AssertCode(t testing.TB, want int, err error) {
+	t.Helper()
+
+	if got := exit.Code(err); got != want {
+		t.Errorf("expected exit code %d (%s), got %d (%s) for error: %v",
+			want, exit.CodeString(want), got, exit.CodeString(got), err)
+	}
+}
+
+// exitCalled is the panic value used to abort fn once it exits.
+type exitCalled struct {
+	code int
+}
+
+// AssertExit runs fn and fails the test if fn does not exit via package exit
+// with code want. The exit func is replaced via exit.SetExitFunc while fn runs
+// and the previous one is restored afterwards, so the test binary is not
+// terminated. Like os.Exit, the replacement does not return: fn stops
+// executing at the point it exits.
+//
+// AssertExit must not be used in parallel tests, since it modifies global
+// state of package exit.
+func AssertExit(t testing.TB, want int, fn func()) {
+	t.Helper()
+
+	got, exited := runExit(fn)
+	if !exited {
+		t.Errorf("expected exit with code %d (%s), but fn returned without exiting",
+			want, exit.CodeString(want))
+		return
+	}
+
+	if got != want {
+		t.Errorf("expected exit code %d (%s), got %d (%s)",
+			want, exit.CodeString(want), got, exit.CodeString(got))
+	}
+}
+
+// runExit runs fn and returns the exit code it exited with. The second return
+// value is false if fn returned without exiting.
+func runExit(fn func()) (code int, exited bool) {
+	prev := exit.SetExitFunc(func(code int) { panic(exitCalled{code}) })
+	defer exit.SetExitFunc(prev)
+
+	defer func() {
+		if v := recover(); v != nil {
+			called, ok := v.(exitCalled)
+			if !ok {
+				panic(v)
+			}
+
+			code, exited = called.code, true
+		}
+	}()
+
+	fn()
+
+	return 0, false
+}
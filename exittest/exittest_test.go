@@ -0,0 +1,121 @@
+package exittest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/martinohmann/exit"
+)
+
+var errUntyped = errors.New("error")
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCode(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		want   int
+		err    error
+		failed bool
+	}{
+		{name: "nil", want: exit.CodeOK, err: nil},
+		{name: "match", want: exit.CodeIOErr, err: exit.Error(exit.CodeIOErr, errUntyped)},
+		{name: "builtin rule", want: exit.CodeErr, err: errUntyped},
+		{name: "mismatch", want: exit.CodeOK, err: exit.Error(exit.CodeIOErr, errUntyped), failed: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			ft := &fakeT{}
+
+			AssertCode(ft, testCase.want, testCase.err)
+
+			if failed := len(ft.errors) > 0; failed != testCase.failed {
+				t.Errorf("expected failed=%t, got %t: %v", testCase.failed, failed, ft.errors)
+			}
+		})
+	}
+}
+
+func TestAssertCode_Message(t *testing.T) {
+	ft := &fakeT{}
+
+	AssertCode(ft, exit.CodeOK, exit.Error(exit.CodeIOErr, errUntyped))
+
+	want := "expected exit code 0 (CodeOK), got 74 (CodeIOErr) for error: error"
+
+	if len(ft.errors) != 1 || ft.errors[0] != want {
+		t.Errorf("expected failure %q, got %q", want, ft.errors)
+	}
+}
+
+func TestAssertExit(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		want   int
+		fn     func()
+		failed bool
+	}{
+		{name: "match", want: exit.CodeNoInput, fn: func() { exit.Exit(exit.Error(exit.CodeNoInput, errUntyped)) }},
+		{name: "success", want: exit.CodeOK, fn: func() { exit.Exit(nil) }},
+		{name: "mismatch", want: exit.CodeOK, fn: func() { exit.Exit(errUntyped) }, failed: true},
+		{name: "no exit", want: exit.CodeOK, fn: func() {}, failed: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			ft := &fakeT{}
+
+			AssertExit(ft, testCase.want, testCase.fn)
+
+			if failed := len(ft.errors) > 0; failed != testCase.failed {
+				t.Errorf("expected failed=%t, got %t: %v", testCase.failed, failed, ft.errors)
+			}
+		})
+	}
+}
+
+func TestAssertExit_StopsExecution(t *testing.T) {
+	var continued bool
+
+	AssertExit(t, exit.CodeUsage, func() {
+		exit.Exit(exit.Error(exit.CodeUsage, errUntyped))
+		continued = true
+	})
+
+	if continued {
+		t.Error("expected fn to stop executing after exit")
+	}
+}
+
+func TestAssertExit_RepanicsOtherPanics(t *testing.T) {
+	defer func() {
+		if v := recover(); v != "boom" {
+			t.Errorf("expected panic %q to be propagated, got %v", "boom", v)
+		}
+	}()
+
+	AssertExit(t, exit.CodeOK, func() { panic("boom") })
+}
+
+func TestAssertExit_RestoresExitFunc(t *testing.T) {
+	code := -1
+
+	prev := exit.SetExitFunc(func(c int) { code = c })
+	defer exit.SetExitFunc(prev)
+
+	AssertExit(t, exit.CodeUsage, func() { exit.Exit(exit.Error(exit.CodeUsage, errUntyped)) })
+
+	exit.Exit(exit.Error(exit.CodeConfig, errUntyped))
+
+	if code != exit.CodeConfig {
+		t.Errorf("expected custom exit func to receive code %d after AssertExit, got %d", exit.CodeConfig, code)
+	}
+}
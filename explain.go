@@ -0,0 +1,428 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"syscall"
+)
+
+// RuleKind identifies a rule that can determine the exit code of an error.
+// Rule kinds are reported via the Rule field of a Decision. The precedence of
+// the builtin rule kinds can be changed via SetCodePrecedence.
+type RuleKind string
+
+// Rule kinds that are always consulted first or last and cannot be reordered.
+const (
+	RuleNil      RuleKind = "nil"       // the error is nil
+	RuleTypedNil RuleKind = "typed nil" // the error is a typed nil, see DetectTypedNil
+	RuleHandler  RuleKind = "handler"   // an error handler, see SetErrorHandler
+	RuleFallback RuleKind = "fallback"  // no other rule matched
+)
+
+// Builtin rule kinds in their default order of precedence.
+const (
+	RuleHelp        RuleKind = "flag.ErrHelp" // the error contains flag.ErrHelp
+	RuleExitError   RuleKind = "ExitError"    // the error contains an ExitError
+	RuleUsage       RuleKind = "usage"        // the error contains an argument error, see TooManyArgsError
+	RuleCodeMethod  RuleKind = "Code() int"   // the error contains a Code() int method
+	RuleType        RuleKind = "type"         // a mapping registered via RegisterType
+	RuleExtractor   RuleKind = "extractor"    // a code extractor, see RegisterCodeExtractor
+	RuleHTTPStatus  RuleKind = "HTTP status"  // the error contains a StatusCoder
+	RuleContext     RuleKind = "context"      // the error contains a context error
+	RuleNet         RuleKind = "net.Error"    // the error contains a net.Error
+	RuleOS          RuleKind = "os"           // the error contains an os sentinel error
+	RuleUnknownUser RuleKind = "os/user"      // the error contains an os/user error
+)
+
+// Decision describes how the exit code of an error was determined.
+type Decision struct {
+	// Code is the exit code, exactly as returned by Code.
+	Code int
+
+	// Rule is the kind of the rule which determined the exit code.
+	Rule RuleKind
+
+	// Matched is the error in err's chain which the rule matched, e.g. the
+	// ExitError for RuleExitError or the syscall.Errno matching
+	// os.ErrNotExist for RuleOS. For rules that cannot tell which error in
+	// the chain they matched, e.g. RuleHandler, it is err itself. For RuleNil
+	// and RuleFallback it is nil.
+	Matched error
+}
+
+// Explain describes how Code determines the exit code for err. This is
+// useful for debugging why an error produces a certain exit code, e.g. via a
+// --debug-exit flag:
+//
+//   d := exit.Explain(err)
+//   log.Printf("exit code %d determined by rule %q matching %v", d.Code, d.Rule, d.Matched)
+//
+// Explain and Code share the same logic, so d.Code always equals Code(err).
+func Explain(err error) Decision {
+	return newExitConfig().decide(err)
+}
+
+// builtinRule determines the exit code for an error. If the rule does not
+// match err, ok is false.
+type builtinRule func(err error) (code int, matched error, ok bool)
+
+// builtinRuleFuncs maps the builtin rule kinds to their implementation.
+var builtinRuleFuncs = map[RuleKind]builtinRule{
+	RuleHelp:        matchHelp,
+	RuleExitError:   matchExitError,
+	RuleUsage:       matchUsage,
+	RuleCodeMethod:  matchCodeMethod,
+	RuleType:        matchType,
+	RuleExtractor:   matchExtractor,
+	RuleHTTPStatus:  matchHTTPStatus,
+	RuleContext:     matchContext,
+	RuleNet:         matchNet,
+	RuleOS:          matchOS,
+	RuleUnknownUser: matchUnknownUser,
+}
+
+// defaultPrecedence is the default order of the builtin rules.
+var defaultPrecedence = []RuleKind{
+	RuleHelp,
+	RuleExitError,
+	RuleUsage,
+	RuleCodeMethod,
+	RuleType,
+	RuleExtractor,
+	RuleHTTPStatus,
+	RuleContext,
+	RuleNet,
+	RuleOS,
+	RuleUnknownUser,
+}
+
+// precedence is the current order of the builtin rules.
+var precedence = defaultPrecedence
+
+// DefaultCodePrecedence returns the default order in which Code consults the
+// builtin rules. See Code for a description of the rules.
+func DefaultCodePrecedence() []RuleKind {
+	return append([]RuleKind(nil), defaultPrecedence...)
+}
+
+// CodePrecedence returns the order in which Code currently consults the
+// builtin rules.
+func CodePrecedence() []RuleKind {
+	return append([]RuleKind(nil), precedence...)
+}
+
+// SetCodePrecedence changes the order in which Code consults the builtin
+// rules, e.g. to let sentinel errors of the os package take precedence over
+// explicit codes of ExitError values:
+//
+//   err := exit.SetCodePrecedence([]exit.RuleKind{exit.RuleOS, exit.RuleExitError})
+//
+// The kinds in order are consulted first. All builtin rule kinds not
+// contained in order are consulted afterwards, in their default order. Error
+// handlers are always consulted before and the default code is always used
+// after all builtin rules. Passing an empty order restores the default.
+//
+// Returns an error if order contains a kind more than once or a kind that
+// does not denote a builtin rule, e.g. RuleHandler. The precedence is left
+// unchanged in this case.
+//
+// Calling SetCodePrecedence is not goroutine-safe. Should be called early in
+// main.
+func SetCodePrecedence(order []RuleKind) error {
+	seen := make(map[RuleKind]bool, len(defaultPrecedence))
+	kinds := make([]RuleKind, 0, len(defaultPrecedence))
+
+	for _, kind := range order {
+		if _, ok := builtinRuleFuncs[kind]; !ok {
+			return fmt.Errorf("invalid rule kind %q", kind)
+		}
+
+		if seen[kind] {
+			return fmt.Errorf("duplicate rule kind %q", kind)
+		}
+
+		seen[kind] = true
+		kinds = append(kinds, kind)
+	}
+
+	for _, kind := range defaultPrecedence {
+		if !seen[kind] {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	precedence = kinds
+	return nil
+}
+
+// builtinCode determines the exit code for err using the builtin rules. If
+// none of them matches, fallback is returned.
+func builtinCode(err error, fallback int) int {
+	return builtinDecision(err, fallback).Code
+}
+
+// builtinDecision determines the exit code for err using the builtin rules.
+// If none of them matches, fallback is used.
+func builtinDecision(err error, fallback int) Decision {
+	return decideBuiltin(err, fallback, false)
+}
+
+// decideBuiltin is like builtinDecision. If innermost is true, RuleExitError
+// uses the innermost instead of the outermost ExitError, see CodeInnermost.
+func decideBuiltin(err error, fallback int, innermost bool) Decision {
+	if err == nil {
+		return Decision{Code: CodeOK, Rule: RuleNil}
+	}
+
+	for _, kind := range precedence {
+		rule := builtinRuleFuncs[kind]
+		if innermost && kind == RuleExitError {
+			rule = matchInnermostExitError
+		}
+
+		if code, matched, ok := rule(err); ok {
+			return Decision{Code: code, Rule: kind, Matched: matched}
+		}
+	}
+
+	return Decision{Code: fallback, Rule: RuleFallback}
+}
+
+func matchHelp(err error) (int, error, bool) {
+	if matched := findIs(err, flag.ErrHelp); matched != nil {
+		return helpExitCode, matched, true
+	}
+
+	return 0, nil, false
+}
+
+func matchUsage(err error) (int, error, bool) {
+	var argErr *argError
+	if errors.As(err, &argErr) {
+		return CodeUsage, argErr, true
+	}
+
+	return 0, nil, false
+}
+
+func matchExitError(err error) (int, error, bool) {
+	exitErr, code, ok := findExitError(err)
+	return code, exitErr, ok
+}
+
+func matchInnermostExitError(err error) (int, error, bool) {
+	exitErr, code, ok := findInnermostExitError(err)
+	return code, exitErr, ok
+}
+
+func matchCodeMethod(err error) (int, error, bool) {
+	var coder interface {
+		error
+		Code() int
+	}
+	if errors.As(err, &coder) {
+		return coder.Code(), coder, true
+	}
+
+	return 0, nil, false
+}
+
+func matchType(err error) (int, error, bool) {
+	code, ok := typeCode(err)
+	return code, err, ok
+}
+
+func matchExtractor(err error) (int, error, bool) {
+	for _, extract := range codeExtractors {
+		if code, ok := extract(err); ok {
+			return code, err, true
+		}
+	}
+
+	return 0, nil, false
+}
+
+func matchHTTPStatus(err error) (int, error, bool) {
+	var coder interface {
+		error
+		StatusCoder
+	}
+	if !errors.As(err, &coder) {
+		return 0, nil, false
+	}
+
+	code, ok := httpStatusCode(coder)
+	return code, coder, ok
+}
+
+func matchContext(err error) (int, error, bool) {
+	if matched := findIs(err, context.DeadlineExceeded); matched != nil {
+		return CodeTempFail, matched, true
+	}
+
+	if matched := findIs(err, context.Canceled); matched != nil {
+		return CodeInterrupt, matched, true
+	}
+
+	return 0, nil, false
+}
+
+func matchNet(err error) (int, error, bool) {
+	var netErr net.Error
+	if !asNetError(err, &netErr) {
+		return 0, nil, false
+	}
+
+	if netErr.Timeout() {
+		return CodeTempFail, netErr, true
+	}
+
+	return CodeUnavailable, netErr, true
+}
+
+// asNetError finds the first error in err's chain that implements net.Error
+// and sets target to it. Since syscall.Errno implements net.Error as well,
+// plain errnos, e.g. those wrapped by *os.PathError, are skipped. Errnos of
+// network operations are wrapped in *net.OpError which is found instead.
+func asNetError(err error, target *net.Error) bool {
+	return !walkErrors(err, func(err error) bool {
+		if _, ok := err.(syscall.Errno); ok {
+			return true
+		}
+
+		netErr, ok := err.(net.Error)
+		if ok {
+			*target = netErr
+		}
+
+		return !ok
+	})
+}
+
+// osCodes maps sentinel errors of the os package to exit codes.
+var osCodes = []struct {
+	target error
+	code   int
+}{
+	{os.ErrNotExist, CodeNoInput},
+	{os.ErrPermission, CodeNoPerm},
+	{os.ErrExist, CodeCantCreat},
+}
+
+// pathOpCodes maps operations of *os.PathError to exit codes.
+var pathOpCodes = map[string]int{
+	"open":   CodeNoInput,
+	"create": CodeCantCreat,
+	"mkdir":  CodeCantCreat,
+	"write":  CodeIOErr,
+}
+
+func matchOS(err error) (int, error, bool) {
+	if matched := findDiskFull(err); matched != nil {
+		return CodeCantCreat, matched, true
+	}
+
+	if code, pathErr, ok := matchPathOp(err); ok {
+		return code, pathErr, true
+	}
+
+	for _, c := range osCodes {
+		if matched := findIs(err, c.target); matched != nil {
+			return c.code, matched, true
+		}
+	}
+
+	return 0, nil, false
+}
+
+// matchPathOp matches the first *os.PathError in err's chain by its operation
+// via pathOpCodes. Path errors wrapping os.ErrPermission or os.ErrExist, and
+// "open" errors wrapping os.ErrNotExist, are left to osCodes, so that they
+// keep their sentinel codes.
+func matchPathOp(err error) (int, error, bool) {
+	var pathErr *os.PathError
+	if !errors.As(err, &pathErr) || errors.Is(pathErr, os.ErrPermission) || errors.Is(pathErr, os.ErrExist) {
+		return 0, nil, false
+	}
+
+	if pathErr.Op == "open" && errors.Is(pathErr, os.ErrNotExist) {
+		return 0, nil, false
+	}
+
+	code, ok := pathOpCodes[pathErr.Op]
+	if !ok {
+		return 0, nil, false
+	}
+
+	return code, pathErr, true
+}
+
+func matchUnknownUser(err error) (int, error, bool) {
+	if isUnknownUser(err) {
+		return CodeNoUser, err, true
+	}
+
+	return 0, nil, false
+}
+
+// findIs returns the first error in err's chain which matches target as
+// defined by errors.Is, i.e. which is equal to target or whose Is method
+// reports true. Returns nil if there is no such error.
+func findIs(err, target error) error {
+	if !errors.Is(err, target) {
+		return nil
+	}
+
+	var matched error
+
+	walkErrors(err, func(e error) bool {
+		if isComparable(e) && e == target {
+			matched = e
+			return false
+		}
+
+		if x, ok := e.(interface{ Is(error) bool }); ok && x.Is(target) {
+			matched = e
+			return false
+		}
+
+		return true
+	})
+
+	if matched == nil {
+		// The chain contains errors with custom As or Is logic that walkErrors
+		// cannot follow.
+		return err
+	}
+
+	return matched
+}
+
+// isComparable reports whether err can be compared via == without panicking.
+func isComparable(err error) bool {
+	return reflect.TypeOf(err).Comparable()
+}
+
+// ErrUnclassified is returned by StrictCode if an error is not matched by any
+// rule and its exit code is the default code.
+var ErrUnclassified = errors.New("error is not classified by any rule")
+
+// StrictCode is like Code but also returns an error wrapping ErrUnclassified
+// if the exit code for err was determined purely by the default code because
+// neither an error handler nor any of the builtin rules matched err. This
+// allows strict pipelines to treat unclassified errors as programming errors.
+// For nil errors, explicitly coded errors and all errors matched by a handler
+// or builtin rule, the returned error is nil.
+func StrictCode(err error) (int, error) {
+	d := Explain(err)
+	if d.Rule == RuleFallback {
+		return d.Code, fmt.Errorf("%w: %v", ErrUnclassified, err)
+	}
+
+	return d.Code, nil
+}
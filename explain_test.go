@@ -0,0 +1,254 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/user"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	exitErr := Error(CodeIOErr, errUntyped)
+	argErr := MissingArgError("FILE")
+	pathErr := &os.PathError{Op: "open", Path: "foo", Err: syscall.ENOENT}
+	netErr := &fakeNetError{timeout: true}
+	coder := codeError{CodeNoHost}
+	unknownUser := user.UnknownUserError("foo")
+
+	for _, testCase := range []struct {
+		name    string
+		err     error
+		code    int
+		rule    RuleKind
+		matched error
+	}{
+		{name: "nil", code: CodeOK, rule: RuleNil},
+		{name: "flag.ErrHelp", err: wrapErr(flag.ErrHelp), code: CodeHelpErr, rule: RuleHelp, matched: flag.ErrHelp},
+		{name: "argument error", err: wrapErr(argErr), code: CodeUsage, rule: RuleExitError, matched: argErr},
+		{name: "ExitError", err: wrapErr(exitErr), code: CodeIOErr, rule: RuleExitError, matched: exitErr},
+		{name: "Code() int", err: wrapErr(coder), code: CodeNoHost, rule: RuleCodeMethod, matched: coder},
+		{name: "http status", err: httpError{404}, code: CodeNoInput, rule: RuleHTTPStatus, matched: httpError{404}},
+		{name: "context", err: wrapErr(context.Canceled), code: CodeInterrupt, rule: RuleContext, matched: context.Canceled},
+		{name: "net.Error", err: wrapErr(netErr), code: CodeTempFail, rule: RuleNet, matched: netErr},
+		{name: "os", err: wrapErr(pathErr), code: CodeNoInput, rule: RuleOS, matched: syscall.ENOENT},
+		{name: "os sentinel", err: os.ErrExist, code: CodeCantCreat, rule: RuleOS, matched: os.ErrExist},
+		{name: "os/user", err: unknownUser, code: CodeNoUser, rule: RuleUnknownUser, matched: unknownUser},
+		{name: "fallback", err: wrapErr(errUntyped), code: CodeErr, rule: RuleFallback},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			d := Explain(testCase.err)
+
+			if d.Rule != testCase.rule {
+				t.Errorf("expected rule %q, got %q", testCase.rule, d.Rule)
+			}
+
+			if d.Code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, d.Code)
+			}
+
+			if d.Matched != testCase.matched {
+				t.Errorf("expected matched error %#v, got %#v", testCase.matched, d.Matched)
+			}
+
+			if code := Code(testCase.err); code != d.Code {
+				t.Errorf("expected Code to return %d, got %d", d.Code, code)
+			}
+		})
+	}
+}
+
+func TestExplain_Handler(t *testing.T) {
+	SetErrorHandler(func(err error) (int, bool) { return CodeSoftware, true })
+	defer SetErrorHandler(nil)
+
+	err := wrapErr(errUntyped)
+
+	if d := Explain(err); d.Rule != RuleHandler || d.Code != CodeSoftware || d.Matched != err {
+		t.Errorf("unexpected decision: %#v", d)
+	}
+}
+
+func TestExplain_Registrations(t *testing.T) {
+	resetTypeMappings(t)
+	defer func() { codeExtractors = nil }()
+
+	RegisterType(&validationError{}, CodeDataErr)
+	RegisterCodeExtractor(func(err error) (int, bool) {
+		_, ok := err.(statusError)
+		return CodeProtocol, ok
+	})
+
+	if d := Explain(&validationError{}); d.Rule != RuleType || d.Code != CodeDataErr {
+		t.Errorf("unexpected decision: %#v", d)
+	}
+
+	if d := Explain(statusError{}); d.Rule != RuleExtractor || d.Code != CodeProtocol {
+		t.Errorf("unexpected decision: %#v", d)
+	}
+}
+
+func TestExplain_TypedNil(t *testing.T) {
+	DetectTypedNil(true)
+	defer DetectTypedNil(false)
+
+	var err *pointerError
+
+	if d := Explain(err); d.Rule != RuleTypedNil || d.Code != CodeOK {
+		t.Errorf("unexpected decision: %#v", d)
+	}
+}
+
+func TestDefaultCodePrecedence(t *testing.T) {
+	// This golden list must match the order documented on Code.
+	golden := []RuleKind{
+		RuleHelp,
+		RuleExitError,
+		RuleUsage,
+		RuleCodeMethod,
+		RuleType,
+		RuleExtractor,
+		RuleHTTPStatus,
+		RuleContext,
+		RuleNet,
+		RuleOS,
+		RuleUnknownUser,
+	}
+
+	if got := DefaultCodePrecedence(); !reflect.DeepEqual(got, golden) {
+		t.Errorf("expected default precedence %v, got %v", golden, got)
+	}
+
+	if got := CodePrecedence(); !reflect.DeepEqual(got, golden) {
+		t.Errorf("expected current precedence %v, got %v", golden, got)
+	}
+}
+
+func TestSetCodePrecedence(t *testing.T) {
+	defer SetCodePrecedence(nil)
+
+	err := Error(CodeConfig, &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist})
+
+	if code := Code(err); code != CodeConfig {
+		t.Fatalf("expected code %d with default precedence, got %d", CodeConfig, code)
+	}
+
+	if err := SetCodePrecedence([]RuleKind{RuleOS, RuleExitError}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if code := Code(err); code != CodeNoInput {
+		t.Errorf("expected code %d with os rule first, got %d", CodeNoInput, code)
+	}
+
+	if d := Explain(err); d.Rule != RuleOS {
+		t.Errorf("expected rule %q, got %q", RuleOS, d.Rule)
+	}
+
+	want := []RuleKind{
+		RuleOS,
+		RuleExitError,
+		RuleHelp,
+		RuleUsage,
+		RuleCodeMethod,
+		RuleType,
+		RuleExtractor,
+		RuleHTTPStatus,
+		RuleContext,
+		RuleNet,
+		RuleUnknownUser,
+	}
+
+	if got := CodePrecedence(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected precedence %v, got %v", want, got)
+	}
+
+	if code := Code(Error(CodeIOErr, errUntyped)); code != CodeIOErr {
+		t.Errorf("expected unrelated codes to be unaffected, got %d", code)
+	}
+
+	if err := SetCodePrecedence(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if code := Code(err); code != CodeConfig {
+		t.Errorf("expected code %d after reset, got %d", CodeConfig, code)
+	}
+}
+
+func TestSetCodePrecedence_Invalid(t *testing.T) {
+	defer SetCodePrecedence(nil)
+
+	for _, order := range [][]RuleKind{
+		{RuleOS, RuleExitError, RuleOS},
+		{RuleHandler},
+		{RuleFallback},
+		{"bogus"},
+	} {
+		if err := SetCodePrecedence(order); err == nil {
+			t.Errorf("expected error for order %v", order)
+		}
+
+		if got := CodePrecedence(); !reflect.DeepEqual(got, DefaultCodePrecedence()) {
+			t.Errorf("expected precedence to be unchanged after invalid order %v, got %v", order, got)
+		}
+	}
+}
+
+func TestStrictCode(t *testing.T) {
+	for _, testCase := range []struct {
+		name         string
+		err          error
+		code         int
+		unclassified bool
+	}{
+		{name: "nil", code: CodeOK},
+		{name: "ExitError", err: wrapErr(Error(CodeIOErr, errUntyped)), code: CodeIOErr},
+		{name: "ExitError with CodeErr", err: Error(CodeErr, errUntyped), code: CodeErr},
+		{name: "flag.ErrHelp", err: wrapErr(flag.ErrHelp), code: CodeHelpErr},
+		{name: "builtin rule", err: os.ErrNotExist, code: CodeNoInput},
+		{name: "untyped error", err: errUntyped, code: CodeErr, unclassified: true},
+		{name: "wrapped untyped error", err: wrapErr(errUntyped), code: CodeErr, unclassified: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, err := StrictCode(testCase.err)
+			if code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+
+			if unclassified := errors.Is(err, ErrUnclassified); unclassified != testCase.unclassified {
+				t.Errorf("expected unclassified to be %t, got error %v", testCase.unclassified, err)
+			}
+		})
+	}
+}
+
+func TestStrictCode_Handler(t *testing.T) {
+	SetErrorHandler(func(err error) (int, bool) { return CodeSoftware, true })
+	defer SetErrorHandler(nil)
+
+	if code, err := StrictCode(errUntyped); code != CodeSoftware || err != nil {
+		t.Errorf("expected (%d, nil), got (%d, %v)", CodeSoftware, code, err)
+	}
+}
+
+func TestCode_HelpPrecedence(t *testing.T) {
+	defer SetCodePrecedence(nil)
+
+	err := Error(CodeUsage, flag.ErrHelp)
+
+	if code := Code(err); code != CodeHelpErr {
+		t.Errorf("expected flag.ErrHelp to win by default, got code %d", code)
+	}
+
+	if err := SetCodePrecedence([]RuleKind{RuleExitError, RuleCodeMethod, RuleHelp}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if code := Code(err); code != CodeUsage {
+		t.Errorf("expected ExitError to win after reordering, got code %d", code)
+	}
+}
@@ -0,0 +1,70 @@
+package exit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Errorw creates a new ExitError with given exit code that carries
+// structured context in the form of alternating keys and values, e.g. for
+// logging them right before calling Exit:
+//
+//   err := exit.Errorw(exit.CodeIOErr, "failed to write file", "path", path, "error", err)
+//
+//   log.Print(err.Error(), err.(exit.Fielder).Fields()...)
+//
+// The message of the error consists of msg followed by the key=value pairs.
+// If one of the values is an error, it is used as the cause of the returned
+// error and can be obtained via errors.Unwrap. A trailing key without value
+// is paired with "(MISSING)", like fmt does for missing arguments.
+func Errorw(code int, msg string, keysAndValues ...interface{}) error {
+	if len(keysAndValues)%2 != 0 {
+		keysAndValues = append(keysAndValues, "(MISSING)")
+	}
+
+	return &fieldsError{
+		exitError: exitError{&fieldsMessage{msg, keysAndValues}, code},
+		fields:    keysAndValues,
+	}
+}
+
+// Fielder is implemented by errors which carry structured context in the
+// form of alternating keys and values.
+type Fielder interface {
+	Fields() []interface{}
+}
+
+type fieldsError struct {
+	exitError
+	fields []interface{}
+}
+
+// Unwrap returns the first error among the values of the error's fields.
+func (e *fieldsError) Unwrap() error {
+	for i := 1; i < len(e.fields); i += 2 {
+		if err, ok := e.fields[i].(error); ok {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *fieldsError) Fields() []interface{} { return e.fields }
+
+type fieldsMessage struct {
+	msg    string
+	fields []interface{}
+}
+
+func (m *fieldsMessage) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(m.msg)
+
+	for i := 0; i < len(m.fields); i += 2 {
+		fmt.Fprintf(&sb, " %v=%v", m.fields[i], m.fields[i+1])
+	}
+
+	return sb.String()
+}
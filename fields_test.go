@@ -0,0 +1,57 @@
+package exit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestErrorw(t *testing.T) {
+	err := Errorw(CodeIOErr, "failed to write file", "path", "/tmp/foo", "attempt", 2)
+
+	if msg := err.Error(); msg != "failed to write file path=/tmp/foo attempt=2" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	if code := Code(err); code != CodeIOErr {
+		t.Errorf("expected code %d, got %d", CodeIOErr, code)
+	}
+
+	var fielder Fielder
+	if !errors.As(wrapErr(err), &fielder) {
+		t.Fatal("expected error to implement Fielder")
+	}
+
+	expected := []interface{}{"path", "/tmp/foo", "attempt", 2}
+	if fields := fielder.Fields(); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, fields)
+	}
+
+	if cause := errors.Unwrap(err); cause != nil {
+		t.Errorf("expected no cause, got %v", cause)
+	}
+}
+
+func TestErrorw_Cause(t *testing.T) {
+	err := Errorw(CodeUnavailable, "request failed", "host", "example.com", "error", errUntyped)
+
+	if msg := err.Error(); msg != "request failed host=example.com error=error" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Error("expected errors.Is to find the cause")
+	}
+
+	if code := Code(err); code != CodeUnavailable {
+		t.Errorf("expected code %d, got %d", CodeUnavailable, code)
+	}
+}
+
+func TestErrorw_MissingValue(t *testing.T) {
+	err := Errorw(CodeErr, "oops", "key")
+
+	if msg := err.Error(); msg != "oops key=(MISSING)" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+}
@@ -0,0 +1,54 @@
+package exit
+
+// grpcCodes maps gRPC status codes as defined by the
+// google.golang.org/grpc/codes package to exit codes.
+var grpcCodes = map[uint32]int{
+	0:  CodeOK,          // OK
+	1:  CodeInterrupt,   // Canceled
+	2:  CodeErr,         // Unknown
+	3:  CodeUsage,       // InvalidArgument
+	4:  CodeTempFail,    // DeadlineExceeded
+	5:  CodeNoInput,     // NotFound
+	6:  CodeCantCreat,   // AlreadyExists
+	7:  CodeNoPerm,      // PermissionDenied
+	8:  CodeTempFail,    // ResourceExhausted
+	9:  CodeDataErr,     // FailedPrecondition
+	10: CodeTempFail,    // Aborted
+	11: CodeDataErr,     // OutOfRange
+	12: CodeUnavailable, // Unimplemented
+	13: CodeSoftware,    // Internal
+	14: CodeTempFail,    // Unavailable
+	15: CodeDataErr,     // DataLoss
+	16: CodeNoPerm,      // Unauthenticated
+}
+
+// FromGRPCCode maps the gRPC status code code to an exit code. This allows to
+// translate errors of gRPC backends without linking the grpc package, e.g.
+// via exit.FromGRPCCode(uint32(status.Code(err))). The mapping is as follows:
+//
+//   0  OK                 -> 0   (CodeOK)
+//   1  Canceled           -> 130 (CodeInterrupt)
+//   2  Unknown            -> 1   (CodeErr)
+//   3  InvalidArgument    -> 64  (CodeUsage)
+//   4  DeadlineExceeded   -> 75  (CodeTempFail)
+//   5  NotFound           -> 66  (CodeNoInput)
+//   6  AlreadyExists      -> 73  (CodeCantCreat)
+//   7  PermissionDenied   -> 77  (CodeNoPerm)
+//   8  ResourceExhausted  -> 75  (CodeTempFail)
+//   9  FailedPrecondition -> 65  (CodeDataErr)
+//   10 Aborted            -> 75  (CodeTempFail)
+//   11 OutOfRange         -> 65  (CodeDataErr)
+//   12 Unimplemented      -> 69  (CodeUnavailable)
+//   13 Internal           -> 70  (CodeSoftware)
+//   14 Unavailable        -> 75  (CodeTempFail)
+//   15 DataLoss           -> 65  (CodeDataErr)
+//   16 Unauthenticated    -> 77  (CodeNoPerm)
+//
+// All other values produce CodeProtocol.
+func FromGRPCCode(code uint32) int {
+	if exitCode, ok := grpcCodes[code]; ok {
+		return exitCode
+	}
+
+	return CodeProtocol
+}
@@ -0,0 +1,36 @@
+package exit
+
+import "testing"
+
+func TestFromGRPCCode(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		code uint32
+		want int
+	}{
+		{name: "OK", code: 0, want: CodeOK},
+		{name: "Canceled", code: 1, want: CodeInterrupt},
+		{name: "Unknown", code: 2, want: CodeErr},
+		{name: "InvalidArgument", code: 3, want: CodeUsage},
+		{name: "DeadlineExceeded", code: 4, want: CodeTempFail},
+		{name: "NotFound", code: 5, want: CodeNoInput},
+		{name: "AlreadyExists", code: 6, want: CodeCantCreat},
+		{name: "PermissionDenied", code: 7, want: CodeNoPerm},
+		{name: "ResourceExhausted", code: 8, want: CodeTempFail},
+		{name: "FailedPrecondition", code: 9, want: CodeDataErr},
+		{name: "Aborted", code: 10, want: CodeTempFail},
+		{name: "OutOfRange", code: 11, want: CodeDataErr},
+		{name: "Unimplemented", code: 12, want: CodeUnavailable},
+		{name: "Internal", code: 13, want: CodeSoftware},
+		{name: "Unavailable", code: 14, want: CodeTempFail},
+		{name: "DataLoss", code: 15, want: CodeDataErr},
+		{name: "Unauthenticated", code: 16, want: CodeNoPerm},
+		{name: "invalid", code: 17, want: CodeProtocol},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := FromGRPCCode(testCase.code); got != testCase.want {
+				t.Errorf("got %d, want %d", got, testCase.want)
+			}
+		})
+	}
+}
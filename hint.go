@@ -0,0 +1,27 @@
+package exit
+
+import "errors"
+
+// Hinter is implemented by errors which carry a hint for the user on how to
+// resolve them.
+type Hinter interface {
+	Hint() string
+}
+
+// Hint returns the hint of the first error in err's chain that implements
+// Hinter. Returns an empty string if there is none.
+func Hint(err error) string {
+	var hinter Hinter
+	if errors.As(err, &hinter) {
+		return hinter.Hint()
+	}
+
+	return ""
+}
+
+type hintError struct {
+	exitError
+	hint string
+}
+
+func (e *hintError) Hint() string { return e.hint }
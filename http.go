@@ -0,0 +1,70 @@
+package exit
+
+import "errors"
+
+// StatusCoder is implemented by errors which carry an HTTP status code, e.g.
+// errors returned by HTTP API clients.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// FromHTTPStatus maps the HTTP status code status to an exit code according
+// to the following table:
+//
+//   1xx, 3xx           -> 76 (CodeProtocol)
+//   2xx                -> 0  (CodeOK)
+//   400                -> 65 (CodeDataErr)
+//   401, 403           -> 77 (CodeNoPerm)
+//   404, 410           -> 66 (CodeNoInput)
+//   408, 429           -> 75 (CodeTempFail)
+//   422                -> 65 (CodeDataErr)
+//   all other 4xx      -> 64 (CodeUsage)
+//   502, 503, 504      -> 75 (CodeTempFail)
+//   all other 5xx      -> 69 (CodeUnavailable)
+//   invalid status     -> 76 (CodeProtocol)
+func FromHTTPStatus(status int) int {
+	switch {
+	case status >= 200 && status < 300:
+		return CodeOK
+	case status >= 400 && status < 500:
+		return clientErrorCode(status)
+	case status >= 500 && status < 600:
+		switch status {
+		case 502, 503, 504:
+			return CodeTempFail
+		default:
+			return CodeUnavailable
+		}
+	default:
+		return CodeProtocol
+	}
+}
+
+func clientErrorCode(status int) int {
+	switch status {
+	case 400, 422:
+		return CodeDataErr
+	case 401, 403:
+		return CodeNoPerm
+	case 404, 410:
+		return CodeNoInput
+	case 408, 429:
+		return CodeTempFail
+	default:
+		return CodeUsage
+	}
+}
+
+// httpStatusCode returns the exit code for the HTTP status of the first error
+// in err's chain that implements StatusCoder. Successful statuses are ignored
+// since err signals a failure regardless.
+func httpStatusCode(err error) (int, bool) {
+	var coder StatusCoder
+	if !errors.As(err, &coder) {
+		return 0, false
+	}
+
+	code := FromHTTPStatus(coder.StatusCode())
+
+	return code, code != CodeOK
+}
@@ -0,0 +1,62 @@
+package exit
+
+import "testing"
+
+type httpError struct{ status int }
+
+func (e httpError) Error() string   { return "http error" }
+func (e httpError) StatusCode() int { return e.status }
+
+func TestFromHTTPStatus(t *testing.T) {
+	for status, code := range map[int]int{
+		0:   CodeProtocol,
+		100: CodeProtocol,
+		200: CodeOK,
+		201: CodeOK,
+		204: CodeOK,
+		301: CodeProtocol,
+		304: CodeProtocol,
+		400: CodeDataErr,
+		401: CodeNoPerm,
+		403: CodeNoPerm,
+		404: CodeNoInput,
+		405: CodeUsage,
+		408: CodeTempFail,
+		409: CodeUsage,
+		410: CodeNoInput,
+		418: CodeUsage,
+		422: CodeDataErr,
+		429: CodeTempFail,
+		500: CodeUnavailable,
+		501: CodeUnavailable,
+		502: CodeTempFail,
+		503: CodeTempFail,
+		504: CodeTempFail,
+		599: CodeUnavailable,
+		600: CodeProtocol,
+		-1:  CodeProtocol,
+	} {
+		if got := FromHTTPStatus(status); got != code {
+			t.Errorf("FromHTTPStatus(%d): got %d, want %d", status, got, code)
+		}
+	}
+}
+
+func TestCode_StatusCoder(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "not found", err: httpError{404}, code: CodeNoInput},
+		{name: "wrapped service unavailable", err: wrapErr(httpError{503}), code: CodeTempFail},
+		{name: "successful status", err: httpError{200}, code: CodeErr},
+		{name: "ExitError takes precedence", err: Error(CodeConfig, httpError{404}), code: CodeConfig},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
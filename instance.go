@@ -0,0 +1,19 @@
+package exit
+
+var instanceTag string
+
+// SetInstanceTag sets a tag identifying the running instance of the program,
+// e.g. a hostname or shard name. This ties exit events to a specific instance
+// in aggregated logs. If set, the tag is included as follows:
+//
+//   - as "instance" field in the exit event sent via SetExitSocket
+//   - as "instance" attribute in the log record emitted via SlogHandler
+//   - as "instance <tag>: " prefix in the reports returned by Registry.Report
+//
+// Passing an empty tag removes it, which is the default.
+//
+// Calling SetInstanceTag is not goroutine-safe. Should be called early in
+// main.
+func SetInstanceTag(tag string) {
+	instanceTag = tag
+}
@@ -0,0 +1,40 @@
+package exit
+
+import "testing"
+
+// setInstanceTag sets tag via SetInstanceTag for the duration of the test.
+func setInstanceTag(t *testing.T, tag string) {
+	SetInstanceTag(tag)
+	t.Cleanup(func() { SetInstanceTag("") })
+}
+
+func TestSetInstanceTag_Report(t *testing.T) {
+	var r Registry
+
+	if err := r.Register("storage", 80, 89); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	setInstanceTag(t, "shard-1")
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "subsystem", err: Error(82, errUntyped), want: "instance shard-1: subsystem storage: exit code 82"},
+		{name: "no subsystem", err: errUntyped, want: "instance shard-1: exit code 1"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := r.Report(testCase.err); got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+
+	SetInstanceTag("")
+
+	if got, want := r.Report(errUntyped), "exit code 1"; got != want {
+		t.Errorf("empty tag: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package exit
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// InputError classifies err which was returned while accessing the input file
+// at path and wraps it into an ExitError. If err is nil it is returned as is.
+//
+// The exit code is chosen as follows:
+//
+//   - CodeNoInput if err is or wraps os.ErrNotExist
+//   - CodeNoPerm if err is or wraps os.ErrPermission
+//   - CodeIOErr for all other errors
+//
+// The resulting error message includes path.
+func InputError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := CodeIOErr
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		code = CodeNoInput
+	case errors.Is(err, os.ErrPermission):
+		code = CodeNoPerm
+	}
+
+	return Errorf(code, "input %s: %w", path, err)
+}
+
+// OutputError classifies err which was returned while creating or writing
+// output and wraps it into an ExitError. If err is nil it is returned as is.
+//
+// The exit code is chosen as follows:
+//
+//   - CodeCantCreat if the disk is full or the quota is exceeded (e.g. ENOSPC
+//     or EDQUOT)
+//   - CodeNoPerm if err is or wraps os.ErrPermission
+//   - CodeIOErr for all other errors
+func OutputError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := CodeIOErr
+
+	switch {
+	case isDiskFull(err):
+		code = CodeCantCreat
+	case errors.Is(err, os.ErrPermission):
+		code = CodeNoPerm
+	}
+
+	return Error(code, err)
+}
+
+// isDiskFull reports whether err signals that the disk is full or the quota
+// is exceeded.
+func isDiskFull(err error) bool {
+	return findDiskFull(err) != nil
+}
+
+// findDiskFull returns the first error in err's chain which signals that the
+// disk is full or the quota is exceeded. Returns nil if there is no such error.
+func findDiskFull(err error) error {
+	for _, target := range diskFullErrors {
+		if matched := findIs(err, target); matched != nil {
+			return matched
+		}
+	}
+
+	return nil
+}
+
+// PermissionError wraps err into an ExitError with code CodeNoPerm if err is
+// or wraps os.ErrPermission (e.g. EACCES or EPERM). The returned error
+// implements Hinter and suggests to retry with elevated privileges. All other
+// errors, including nil, are returned as is.
+func PermissionError(err error) error {
+	if !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+
+	return &hintError{
+		exitError: exitError{err, CodeNoPerm},
+		hint:      "try running with elevated privileges",
+	}
+}
+
+// CloseOrWrap closes c and, if the pointed-to error is nil, sets it to the
+// error returned by Close wrapped into an ExitError with given code. If the
+// pointed-to error is already non-nil, it is left as is so that the primary
+// error wins and the error returned by Close is discarded. Meant to be used
+// in defer statements:
+//
+//   func writeOutput(path string) (err error) {
+//     f, err := os.Create(path)
+//     if err != nil {
+//       return exit.OutputError(err)
+//     }
+//     defer exit.CloseOrWrap(f, &err, exit.CodeIOErr)
+//
+//     // write to f
+//   }
+func CloseOrWrap(c io.Closer, err *error, code int) {
+	closeErr := c.Close()
+	if *err == nil {
+		*err = Error(code, closeErr)
+	}
+}
@@ -0,0 +1,152 @@
+package exit
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestInputError(t *testing.T) {
+	if err := InputError("input.txt", nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+		msg  string
+	}{
+		{
+			name: "not exist",
+			err:  os.ErrNotExist,
+			code: CodeNoInput,
+			msg:  "input input.txt: file does not exist",
+		},
+		{
+			name: "permission",
+			err:  wrapErr(os.ErrPermission),
+			code: CodeNoPerm,
+			msg:  "input input.txt: wrapped: permission denied",
+		},
+		{
+			name: "generic",
+			err:  errors.New("unexpected EOF"),
+			code: CodeIOErr,
+			msg:  "input input.txt: unexpected EOF",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := InputError("input.txt", testCase.err)
+
+			if code := Code(err); code != testCase.code {
+				t.Errorf("got code %d, want %d", code, testCase.code)
+			}
+
+			if err.Error() != testCase.msg {
+				t.Errorf("got msg %q, want %q", err.Error(), testCase.msg)
+			}
+
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("errors.Is(%#v, %#v) returned false", err, testCase.err)
+			}
+		})
+	}
+
+	_, err := os.Open("/nonexistent/input.txt")
+	if code := Code(InputError("/nonexistent/input.txt", err)); code != CodeNoInput {
+		t.Errorf("got code %d, want %d", code, CodeNoInput)
+	}
+}
+
+func TestOutputError(t *testing.T) {
+	if err := OutputError(nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "permission", err: &os.PathError{Op: "open", Path: "out.txt", Err: os.ErrPermission}, code: CodeNoPerm},
+		{name: "generic", err: errors.New("short write"), code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := OutputError(testCase.err)
+
+			if code := Code(err); code != testCase.code {
+				t.Errorf("got code %d, want %d", code, testCase.code)
+			}
+
+			if !errors.Is(err, testCase.err) {
+				t.Errorf("errors.Is(%#v, %#v) returned false", err, testCase.err)
+			}
+		})
+	}
+}
+
+func TestPermissionError(t *testing.T) {
+	if err := PermissionError(nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	err := PermissionError(errUntyped)
+	if err != errUntyped {
+		t.Errorf("got %#v, want %#v", err, errUntyped)
+	}
+
+	if hint := Hint(err); hint != "" {
+		t.Errorf("got hint %q for non-permission error, want none", hint)
+	}
+
+	origErr := &os.PathError{Op: "listen", Path: ":80", Err: os.ErrPermission}
+
+	err = PermissionError(origErr)
+	if code := Code(err); code != CodeNoPerm {
+		t.Errorf("got code %d, want %d", code, CodeNoPerm)
+	}
+
+	if hint := Hint(wrapErr(err)); hint != "try running with elevated privileges" {
+		t.Errorf("got hint %q, want %q", hint, "try running with elevated privileges")
+	}
+
+	if wrappedErr := errors.Unwrap(err); wrappedErr != origErr {
+		t.Errorf("errors.Unwrap(err), got: %#v, want: %#v", wrappedErr, origErr)
+	}
+}
+
+type closerFunc func() error
+
+func (fn closerFunc) Close() error { return fn() }
+
+func TestCloseOrWrap(t *testing.T) {
+	errClose := errors.New("close failed")
+	failingCloser := closerFunc(func() error { return errClose })
+
+	var err error
+	CloseOrWrap(closerFunc(func() error { return nil }), &err, CodeIOErr)
+
+	if err != nil {
+		t.Errorf("successful close: got %#v, want nil", err)
+	}
+
+	CloseOrWrap(failingCloser, &err, CodeIOErr)
+
+	if code := Code(err); code != CodeIOErr {
+		t.Errorf("nil primary error: got code %d, want %d", code, CodeIOErr)
+	}
+
+	if !errors.Is(err, errClose) {
+		t.Errorf("nil primary error: errors.Is(%#v, %#v) returned false", err, errClose)
+	}
+
+	err = Error(CodeNoPerm, errUntyped)
+	primaryErr := err
+
+	CloseOrWrap(failingCloser, &err, CodeIOErr)
+
+	if err != primaryErr {
+		t.Errorf("non-nil primary error: got %#v, want %#v", err, primaryErr)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestOutputError_DiskFull(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+	}{
+		{name: "disk full", err: &os.PathError{Op: "write", Path: "out.txt", Err: syscall.ENOSPC}},
+		{name: "quota exceeded", err: wrapErr(&os.PathError{Op: "write", Path: "out.txt", Err: syscall.EDQUOT})},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(OutputError(testCase.err)); code != CodeCantCreat {
+				t.Errorf("got code %d, want %d", code, CodeCantCreat)
+			}
+		})
+	}
+}
+
+func TestCode_DiskFull(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+	}{
+		{name: "bare ENOSPC", err: syscall.ENOSPC},
+		{name: "write ENOSPC", err: &os.PathError{Op: "write", Path: "out.txt", Err: syscall.ENOSPC}},
+		{name: "write EDQUOT", err: wrapErr(&os.PathError{Op: "write", Path: "out.txt", Err: syscall.EDQUOT})},
+		{name: "create ENOSPC", err: &os.PathError{Op: "open", Path: "out.txt", Err: syscall.ENOSPC}},
+		{name: "mkdir EDQUOT", err: &os.PathError{Op: "mkdir", Path: "out", Err: syscall.EDQUOT}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != CodeCantCreat {
+				t.Errorf("got code %d, want %d", code, CodeCantCreat)
+			}
+		})
+	}
+
+	if code := Code(&os.PathError{Op: "write", Path: "out.txt", Err: syscall.EIO}); code != CodeIOErr {
+		t.Errorf("other write errors: got code %d, want %d", code, CodeIOErr)
+	}
+}
+
+func TestPermissionError_Errno(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.EACCES, syscall.EPERM} {
+		err := PermissionError(&os.SyscallError{Syscall: "bind", Err: errno})
+
+		if code := Code(err); code != CodeNoPerm {
+			t.Errorf("%v: got code %d, want %d", errno, code, CodeNoPerm)
+		}
+
+		if Hint(err) == "" {
+			t.Errorf("%v: got no hint", errno)
+		}
+	}
+}
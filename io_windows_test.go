@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package exit
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestCode_DiskFull(t *testing.T) {
+	for _, errno := range []syscall.Errno{errorHandleDiskFull, errorDiskFull, errorDiskQuota} {
+		err := &os.PathError{Op: "write", Path: "out.txt", Err: errno}
+
+		if code := Code(err); code != CodeCantCreat {
+			t.Errorf("%d: got code %d, want %d", errno, code, CodeCantCreat)
+		}
+	}
+}
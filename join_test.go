@@ -0,0 +1,37 @@
+//go:build go1.20
+// +build go1.20
+
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCode_JoinedErrors(t *testing.T) {
+	ioErr := Error(CodeIOErr, errUntyped)
+	permErr := Error(CodeNoPerm, errUntyped)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "io then perm", err: errors.Join(ioErr, permErr), code: CodeNoPerm},
+		{name: "perm then io", err: errors.Join(permErr, ioErr), code: CodeNoPerm},
+		{name: "wrapped branches", err: errors.Join(wrapErr(ioErr), wrapErr(permErr)), code: CodeNoPerm},
+		{name: "uncoded branch", err: errors.Join(errUntyped, ioErr), code: CodeIOErr},
+		{name: "outer code wins", err: Error(CodeUsage, errors.Join(ioErr, permErr)), code: CodeUsage},
+		{name: "nested joins", err: errors.Join(ioErr, errors.Join(errUntyped, permErr)), code: CodeNoPerm},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+
+			if code, ok := ExplicitCode(testCase.err); !ok || code != testCase.code {
+				t.Errorf("expected explicit code (%d, true), got (%d, %t)", testCase.code, code, ok)
+			}
+		})
+	}
+}
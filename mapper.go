@@ -0,0 +1,116 @@
+package exit
+
+import "sync"
+
+// Handler may provide an exit code for err. If it determined a suitable exit
+// code for err it should signal this by setting the second return value to
+// true.
+type Handler interface {
+	Code(err error) (code int, handled bool)
+}
+
+// HandlerFunc adapts an ordinary func, e.g. an ErrorHandlerFunc, to the
+// Handler interface.
+type HandlerFunc func(err error) (code int, handled bool)
+
+// Code implements Handler.
+func (fn HandlerFunc) Code(err error) (code int, handled bool) {
+	return fn(err)
+}
+
+// fallbackHandler is implemented by handlers which make use of the exit code
+// the builtin rules determine for an error. Mapper passes them the default
+// code in effect for the current call, e.g. the one set via WithDefaultCode,
+// instead of the global one.
+type fallbackHandler interface {
+	codeWithFallback(err error, fallback int) (code int, handled bool)
+}
+
+// Mapper maps errors to exit codes by consulting an ordered list of handlers
+// before falling back to the builtin rules. This allows to build and test
+// mapping logic in isolation without mutating global state. The package level
+// functions like Code and AddErrorHandler delegate to a default Mapper.
+//
+// The zero value is a Mapper without handlers ready to use. A Mapper is
+// goroutine-safe.
+type Mapper struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// NewMapper creates a new *Mapper which consults handlers in the given order.
+func NewMapper(handlers ...Handler) *Mapper {
+	m := &Mapper{}
+	m.Set(handlers...)
+	return m
+}
+
+// Add appends h to the list of handlers.
+func (m *Mapper) Add(h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Copy on write, so that callers of m.list can safely iterate over the
+	// slice they obtained without holding the lock.
+	handlers := make([]Handler, len(m.handlers), len(m.handlers)+1)
+	copy(handlers, m.handlers)
+	m.handlers = append(handlers, h)
+}
+
+// Set replaces the list of handlers with handlers. Calling Set without
+// arguments removes all handlers.
+func (m *Mapper) Set(handlers ...Handler) {
+	var list []Handler
+	if len(handlers) > 0 {
+		list = make([]Handler, len(handlers))
+		copy(list, handlers)
+	}
+
+	m.mu.Lock()
+	m.handlers = list
+	m.mu.Unlock()
+}
+
+// Code picks a suitable exit code for err. If err is nil the returned code is
+// 0. Otherwise the handlers are consulted in order and the first one that
+// handles err determines the exit code. If no handler handles err, the exit
+// code is determined by the builtin rules.
+//
+// Unlike the package level Code func, it does not normalize the exit code.
+//
+// See the package level Code func for a description of the builtin rules.
+func (m *Mapper) Code(err error) int {
+	return m.decide(err, defaultErrorCode, false).Code
+}
+
+// decide determines the exit code for err like Code but uses fallback if
+// neither a handler nor any of the builtin rules matches err. If innermost is
+// true, the innermost ExitError is used, see CodeInnermost.
+func (m *Mapper) decide(err error, fallback int, innermost bool) Decision {
+	if err != nil {
+		for _, h := range m.list() {
+			if code, handled := handle(h, err, fallback); handled {
+				return Decision{Code: code, Rule: RuleHandler, Matched: err}
+			}
+		}
+	}
+
+	return decideBuiltin(err, fallback, innermost)
+}
+
+// handle calls h for err, passing fallback if h is a fallbackHandler.
+func handle(h Handler, err error, fallback int) (code int, handled bool) {
+	if fh, ok := h.(fallbackHandler); ok {
+		return fh.codeWithFallback(err, fallback)
+	}
+
+	return h.Code(err)
+}
+
+// list returns the current list of handlers. The returned slice must not be
+// modified.
+func (m *Mapper) list() []Handler {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.handlers
+}
@@ -0,0 +1,63 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapper(t *testing.T) {
+	errCustom := errors.New("custom")
+
+	m := NewMapper(
+		HandlerFunc(func(err error) (int, bool) {
+			if errors.Is(err, errCustom) {
+				return CodeDataErr, true
+			}
+			return 0, false
+		}),
+	)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "handled", err: wrapErr(errCustom), code: CodeDataErr},
+		{name: "builtin fallback", err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := m.Code(testCase.err); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+
+	if got := Code(errCustom); got != CodeErr {
+		t.Errorf("expected Mapper to not affect the package level Code, got %d", got)
+	}
+}
+
+func TestMapper_AddSet(t *testing.T) {
+	var m Mapper
+
+	m.Add(HandlerFunc(func(err error) (int, bool) { return 0, false }))
+	m.Add(HandlerFunc(func(err error) (int, bool) { return CodeConfig, true }))
+
+	if got := m.Code(errUntyped); got != CodeConfig {
+		t.Errorf("got %d, want %d", got, CodeConfig)
+	}
+
+	m.Set(HandlerFunc(func(err error) (int, bool) { return CodeUsage, true }))
+
+	if got := m.Code(errUntyped); got != CodeUsage {
+		t.Errorf("got %d, want %d", got, CodeUsage)
+	}
+
+	m.Set()
+
+	if got := m.Code(errUntyped); got != CodeErr {
+		t.Errorf("got %d, want %d", got, CodeErr)
+	}
+}
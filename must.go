@@ -0,0 +1,22 @@
+//go:build go1.21
+// +build go1.21
+
+package exit
+
+// Must returns v if err is nil. Otherwise it calls Exit with err and does not
+// return. This tidies up setup code in main:
+//
+//   cfg := exit.Must(loadConfig(path))
+//
+// Must is only available when building with Go 1.21 or later. Older
+// toolchains reject type parameters because go.mod declares an older language
+// version, and only Go 1.21 and later allow build constraints to raise it.
+//
+// See Exit for more information.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		Exit(err)
+	}
+
+	return v
+}
@@ -0,0 +1,24 @@
+//go:build go1.21
+// +build go1.21
+
+package exit
+
+import "testing"
+
+func TestMust(t *testing.T) {
+	code := captureExit(t)
+
+	if v := Must("value", nil); v != "value" {
+		t.Errorf("expected value to be passed through, got %q", v)
+	}
+
+	if *code != -1 {
+		t.Errorf("expected no exit on success, got code %d", *code)
+	}
+
+	Must(42, Error(CodeConfig, errUntyped))
+
+	if *code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, *code)
+	}
+}
@@ -0,0 +1,61 @@
+package exit
+
+import "reflect"
+
+// MustExit is a defensive variant of Exit which guards against common
+// pitfalls when passing errors around:
+//
+//   - If err is a typed nil, i.e. a non-nil error interface wrapping a nil
+//     pointer, map, slice, func or chan value, it is treated like a nil error
+//     and the program exits with code 0. This usually happens when a function
+//     returns a nil *MyError as error. Typed nils are detected via
+//     reflection.
+//   - If no meaningful exit code can be obtained for a non-nil err, e.g.
+//     because computing it panics, because it is 0 or because it is out of
+//     the range 0-255, the program exits with CodeSoftware.
+//
+// Otherwise MustExit behaves like Exit.
+func MustExit(err error) {
+	if isTypedNil(err) {
+		err = nil
+	}
+
+	exit(err, mustCode(err), osExit, false)
+}
+
+// mustCode returns the exit code for err or CodeSoftware if err is non-nil and
+// does not produce a meaningful code.
+func mustCode(err error) (code int) {
+	if err == nil {
+		return CodeOK
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			code = CodeSoftware
+		}
+	}()
+
+	code = Code(err)
+	if code == CodeOK || !isValidCode(code) {
+		return CodeSoftware
+	}
+
+	return code
+}
+
+// isTypedNil reports whether err is a non-nil interface holding a nil value.
+func isTypedNil(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	v := reflect.ValueOf(err)
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,38 @@
+package exit
+
+import "testing"
+
+type panickingError struct{}
+
+func (*panickingError) Error() string { return "panicking" }
+func (*panickingError) ExitCode() int { panic("boom") }
+
+func TestMustExit(t *testing.T) {
+	var typedNil *exitError
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "nil", code: CodeOK},
+		{name: "typed nil *exitError", err: typedNil, code: CodeOK},
+		{name: "typed nil slice", err: multiError(nil), code: CodeOK},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+		{name: "ExitError", err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "ExitError with code 0", err: Error(CodeOK, errUntyped), code: CodeSoftware},
+		{name: "ExitError with code out of range", err: Error(300, errUntyped), code: CodeSoftware},
+		{name: "negative code", err: Error(-1, errUntyped), code: CodeSoftware},
+		{name: "panicking ExitError", err: &panickingError{}, code: CodeSoftware},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			MustExit(testCase.err)
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package exit
+
+import (
+	"errors"
+	"os/user"
+	"strings"
+)
+
+// noUserPatterns are lowercase message fragments which indicate that an
+// addressee or user is unknown.
+var noUserPatterns = []string{
+	"unknown recipient",
+	"unknown user",
+	"user unknown",
+	"user not found",
+	"no such user",
+	"addressee unknown",
+}
+
+// NoUserError creates an ExitError with code CodeNoUser signaling that the
+// recipient addr is unknown.
+func NoUserError(addr string) error {
+	return Errorf(CodeNoUser, "unknown recipient: %s", addr)
+}
+
+// IsNoUserError reports whether the message of err matches one of the common
+// "user not found" or "unknown recipient" patterns. Matching is case
+// insensitive. Returns false if err is nil.
+func IsNoUserError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	for _, pattern := range noUserPatterns {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NoUserHandler is an ErrorHandlerFunc which maps errors recognized by
+// IsNoUserError to CodeNoUser. It can be registered via AddErrorHandler:
+//
+//   exit.AddErrorHandler(exit.NoUserHandler)
+func NoUserHandler(err error) (code int, handled bool) {
+	if IsNoUserError(err) {
+		return CodeNoUser, true
+	}
+
+	return 0, false
+}
+
+// isUnknownUser reports whether err contains one of the errors returned by
+// the os/user package if a user or group cannot be found.
+func isUnknownUser(err error) bool {
+	var (
+		unknownUserErr    user.UnknownUserError
+		unknownUserIDErr  user.UnknownUserIdError
+		unknownGroupErr   user.UnknownGroupError
+		unknownGroupIDErr user.UnknownGroupIdError
+	)
+
+	return errors.As(err, &unknownUserErr) ||
+		errors.As(err, &unknownUserIDErr) ||
+		errors.As(err, &unknownGroupErr) ||
+		errors.As(err, &unknownGroupIDErr)
+}
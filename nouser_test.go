@@ -0,0 +1,73 @@
+package exit
+
+import (
+	"errors"
+	"os/user"
+	"testing"
+)
+
+func TestNoUserError(t *testing.T) {
+	err := NoUserError("jane@example.com")
+
+	if code := Code(err); code != CodeNoUser {
+		t.Errorf("got code %d, want %d", code, CodeNoUser)
+	}
+
+	want := "unknown recipient: jane@example.com"
+
+	if err.Error() != want {
+		t.Errorf("got msg %q, want %q", err.Error(), want)
+	}
+}
+
+func TestIsNoUserError(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil error"},
+		{name: "untyped error", err: errUntyped},
+		{name: "NoUserError", err: NoUserError("jane"), want: true},
+		{name: "user not found", err: errors.New("User not found: jane"), want: true},
+		{name: "wrapped no such user", err: wrapErr(errors.New("no such user")), want: true},
+		{name: "550 unknown user", err: errors.New("550 5.1.1 <jane>: Recipient address rejected: User unknown"), want: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := IsNoUserError(testCase.err); got != testCase.want {
+				t.Errorf("got %t, want %t", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNoUserHandler(t *testing.T) {
+	SetErrorHandler(NoUserHandler)
+	defer SetErrorHandler(nil)
+
+	if code := Code(errors.New("unknown recipient")); code != CodeNoUser {
+		t.Errorf("got code %d, want %d", code, CodeNoUser)
+	}
+
+	if code := Code(errUntyped); code != CodeErr {
+		t.Errorf("got code %d, want %d", code, CodeErr)
+	}
+}
+
+func TestCode_UnknownUser(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+	}{
+		{name: "UnknownUserError", err: user.UnknownUserError("jane")},
+		{name: "UnknownUserIdError", err: user.UnknownUserIdError(1234)},
+		{name: "UnknownGroupError", err: user.UnknownGroupError("staff")},
+		{name: "wrapped UnknownGroupIdError", err: wrapErr(user.UnknownGroupIdError("1234"))},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != CodeNoUser {
+				t.Errorf("got code %d, want %d", code, CodeNoUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package exit
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	onExitMu       sync.Mutex
+	onExitFns      []func()
+	maxOnExit      int
+	panicOnMaxExit bool
+)
+
+// OnExit registers fn to be run by Exit right before the program exits.
+// Functions are run in reverse order of their registration, regardless of
+// the exit code. Each function is run at most once. If a function panics, the
+// panic is recovered and reported to stderr, so that the remaining functions
+// still run.
+//
+// If a limit was set via SetMaxOnExit and it is reached, fn is not registered
+// and a warning is written to stderr. If SetPanicOnMaxOnExit was enabled,
+// OnExit panics instead.
+//
+// OnExit is goroutine-safe.
+func OnExit(fn func()) {
+	onExitMu.Lock()
+	defer onExitMu.Unlock()
+
+	if maxOnExit > 0 && len(onExitFns) >= maxOnExit {
+		msg := fmt.Sprintf("exit: limit of %d OnExit functions reached, possible leak", maxOnExit)
+		if panicOnMaxExit {
+			panic(msg)
+		}
+
+		fmt.Fprintf(errWriter, "%s, ignoring function\n", msg)
+		return
+	}
+
+	onExitFns = append(onExitFns, fn)
+}
+
+// ClearExitHooks removes all functions registered via OnExit without running
+// them. This is mostly useful in tests.
+//
+// ClearExitHooks is goroutine-safe.
+func ClearExitHooks() {
+	onExitMu.Lock()
+	onExitFns = nil
+	onExitMu.Unlock()
+}
+
+// SetMaxOnExit limits the number of functions that can be registered via
+// OnExit to n. This helps to detect leaks that are caused by registering
+// functions in a loop. A value of n smaller than 1 removes the limit, which
+// is the default.
+func SetMaxOnExit(n int) {
+	onExitMu.Lock()
+	maxOnExit = n
+	onExitMu.Unlock()
+}
+
+// SetPanicOnMaxOnExit controls whether OnExit panics instead of printing a
+// warning when the limit set via SetMaxOnExit is exceeded.
+func SetPanicOnMaxOnExit(enabled bool) {
+	onExitMu.Lock()
+	panicOnMaxExit = enabled
+	onExitMu.Unlock()
+}
+
+// runOnExit runs and removes all functions registered via OnExit in reverse
+// order of their registration.
+func runOnExit() {
+	onExitMu.Lock()
+	fns := onExitFns
+	onExitFns = nil
+	onExitMu.Unlock()
+
+	for i := len(fns) - 1; i >= 0; i-- {
+		runExitHook(fns[i])
+	}
+}
+
+// runExitHook runs fn and recovers from panics.
+func runExitHook(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(errWriter, "exit: OnExit function panicked: %v\n", r)
+		}
+	}()
+
+	fn()
+}
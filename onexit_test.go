@@ -0,0 +1,116 @@
+package exit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOnExit(t *testing.T) {
+	code := captureExit(t)
+
+	var order []int
+
+	OnExit(func() { order = append(order, 1) })
+	OnExit(func() { order = append(order, 2) })
+
+	Exit(nil)
+
+	if *code != CodeOK {
+		t.Errorf("got code %d, want %d", *code, CodeOK)
+	}
+
+	if want := []int{2, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("got order %v, want %v", order, want)
+	}
+
+	Exit(nil)
+
+	if len(order) != 2 {
+		t.Errorf("expected OnExit functions to run only once, got order %v", order)
+	}
+}
+
+func TestOnExit_Panic(t *testing.T) {
+	code := captureExit(t)
+	buf := captureOutput(t)
+
+	var order []int
+
+	OnExit(func() { order = append(order, 1) })
+	OnExit(func() { panic("boom") })
+	OnExit(func() { order = append(order, 3) })
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if *code != CodeIOErr {
+		t.Errorf("got code %d, want %d", *code, CodeIOErr)
+	}
+
+	if want := []int{3, 1}; !reflect.DeepEqual(order, want) {
+		t.Errorf("got order %v, want %v", order, want)
+	}
+
+	if want := "exit: OnExit function panicked: boom\n"; buf.String() != want {
+		t.Errorf("got output %q, want %q", buf.String(), want)
+	}
+}
+
+func TestClearExitHooks(t *testing.T) {
+	captureExit(t)
+
+	var called bool
+
+	OnExit(func() { called = true })
+	ClearExitHooks()
+
+	Exit(nil)
+
+	if called {
+		t.Error("expected cleared OnExit function not to run")
+	}
+}
+
+func TestSetMaxOnExit(t *testing.T) {
+	captureExit(t)
+	buf := captureOutput(t)
+
+	SetMaxOnExit(2)
+	defer SetMaxOnExit(0)
+
+	var calls int
+
+	for i := 0; i < 3; i++ {
+		OnExit(func() { calls++ })
+	}
+
+	if !strings.Contains(buf.String(), "limit of 2 OnExit functions reached") {
+		t.Errorf("expected warning, got %q", buf.String())
+	}
+
+	Exit(nil)
+
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestSetPanicOnMaxOnExit(t *testing.T) {
+	SetMaxOnExit(1)
+	SetPanicOnMaxOnExit(true)
+	defer func() {
+		SetMaxOnExit(0)
+		SetPanicOnMaxOnExit(false)
+		runOnExit()
+	}()
+
+	OnExit(func() {})
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected OnExit to panic")
+		}
+	}()
+
+	OnExit(func() {})
+}
@@ -0,0 +1,89 @@
+package exit
+
+import "io"
+
+// Option configures the behavior of a single call to Exit.
+type Option func(*exitConfig)
+
+// exitConfig holds the configuration of a single call to Exit.
+type exitConfig struct {
+	writer      io.Writer
+	exitFn      func(int)
+	clamp       bool
+	defaultCode int
+	innermost   bool
+}
+
+// newExitConfig creates a new exitConfig from the global configuration and
+// applies opts to it.
+func newExitConfig(opts ...Option) *exitConfig {
+	cfg := &exitConfig{
+		exitFn:      osExit,
+		clamp:       clampCodes,
+		defaultCode: defaultErrorCode,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// code determines the exit code for err according to cfg.
+func (cfg *exitConfig) code(err error) int {
+	return cfg.decide(err).Code
+}
+
+// decide determines the exit code for err according to cfg and describes how
+// it was determined.
+func (cfg *exitConfig) decide(err error) Decision {
+	if detectTypedNil && isTypedNil(err) {
+		return Decision{Code: CodeOK, Rule: RuleTypedNil, Matched: err}
+	}
+
+	d := defaultMapper.decide(err, cfg.defaultCode, cfg.innermost)
+
+	if cfg.clamp {
+		d.Code = NormalizeCode(d.Code)
+	}
+
+	return d
+}
+
+// WithWriter makes Exit write the message of the error followed by a newline
+// to w before exiting, like Fatal does for stderr. Nothing is written if the
+// error is nil or its message is empty. Passing nil disables writing, which
+// is the default.
+func WithWriter(w io.Writer) Option {
+	return func(cfg *exitConfig) {
+		cfg.writer = w
+	}
+}
+
+// WithExitFunc makes Exit call fn with the exit code instead of os.Exit. See
+// ExitWith for more information. Passing nil is a no-op.
+func WithExitFunc(fn func(int)) Option {
+	return func(cfg *exitConfig) {
+		if fn != nil {
+			cfg.exitFn = fn
+		}
+	}
+}
+
+// WithClamp controls whether Exit normalizes the exit code via
+// NormalizeCode, overriding the global setting of ClampCodes.
+func WithClamp(enabled bool) Option {
+	return func(cfg *exitConfig) {
+		cfg.clamp = enabled
+	}
+}
+
+// WithDefaultCode sets the exit code for errors which are not matched by any
+// error handler or builtin rule, overriding the global setting of
+// SetDefaultErrorCode.
+func WithDefaultCode(code int) Option {
+	return func(cfg *exitConfig) {
+		cfg.defaultCode = code
+	}
+}
@@ -0,0 +1,130 @@
+package exit
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExit_Options(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		err    error
+		opts   []Option
+		write  bool
+		code   int
+		output string
+	}{
+		{name: "no options", err: errUntyped, code: CodeErr},
+		{name: "writer", err: Error(CodeIOErr, errUntyped), write: true, code: CodeIOErr, output: "error\n"},
+		{name: "writer with nil error", write: true, code: CodeOK},
+		{name: "nil writer", err: errUntyped, write: true, opts: []Option{WithWriter(nil)}, code: CodeErr},
+		{name: "clamp", err: Error(300, errUntyped), opts: []Option{WithClamp(true)}, code: 44},
+		{name: "default code", err: errUntyped, opts: []Option{WithDefaultCode(CodeSoftware)}, code: CodeSoftware},
+		{name: "default code keeps explicit codes", err: Error(CodeErr, errUntyped), opts: []Option{WithDefaultCode(CodeSoftware)}, code: CodeErr},
+		{
+			name: "combined",
+			err:  wrapErr(errUntyped),
+			opts: []Option{
+				WithDefaultCode(256 + CodeSoftware),
+				WithClamp(true),
+			},
+			write:  true,
+			code:   CodeSoftware,
+			output: "wrapped: error\n",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			osExitCode := captureExit(t)
+
+			var (
+				code int
+				buf  bytes.Buffer
+			)
+
+			opts := []Option{WithExitFunc(func(c int) { code = c })}
+
+			if testCase.write {
+				opts = append(opts, WithWriter(&buf))
+			}
+
+			opts = append(opts, testCase.opts...)
+
+			Exit(testCase.err, opts...)
+
+			if *osExitCode != -1 {
+				t.Errorf("expected os.Exit not to be called, got code %d", *osExitCode)
+			}
+
+			if code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+
+			if output := buf.String(); output != testCase.output {
+				t.Errorf("expected output %q, got %q", testCase.output, output)
+			}
+		})
+	}
+}
+
+func TestExit_OptionsDoNotModifyGlobalState(t *testing.T) {
+	captureExit(t)
+
+	Exit(errUntyped, WithClamp(true), WithDefaultCode(CodeSoftware))
+
+	if clampCodes {
+		t.Error("expected ClampCodes to remain disabled")
+	}
+
+	if code := Code(errUntyped); code != CodeErr {
+		t.Errorf("expected code %d, got %d", CodeErr, code)
+	}
+}
+
+func TestWithDefaultCode_Handlers(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		install func(t *testing.T)
+		code    int
+	}{
+		{
+			name: "SetErrorHandler2",
+			install: func(t *testing.T) {
+				SetErrorHandler2(func(err error, defaultCode int) (int, bool) {
+					return defaultCode, true
+				})
+			},
+			code: CodeSoftware,
+		},
+		{
+			name: "DefaultHandler",
+			install: func(t *testing.T) {
+				SetErrorHandler(DefaultHandler)
+			},
+			code: CodeSoftware,
+		},
+		{
+			name: "LoadCodeOverridesFromEnv",
+			install: func(t *testing.T) {
+				fakeEnv(t, []string{"MYAPP_EXIT_SOFTWARE=90"})
+
+				if err := LoadCodeOverridesFromEnv("MYAPP_EXIT"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+			code: 90,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+			defer ClearErrorHandlers()
+
+			testCase.install(t)
+
+			Exit(errUntyped, WithDefaultCode(CodeSoftware))
+
+			if *code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, *code)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+type panicMapping struct {
+	match func(interface{}) bool
+	code  int
+}
+
+var (
+	panicMappingsMu sync.RWMutex
+	panicMappings   []panicMapping
+	printPanicStack bool
+)
+
+// RegisterPanicMapping registers a mapping from recovered panic values for
+// which match returns true to code. Mappings are consulted by PanicCode in
+// order of their registration before the builtin rules.
+//
+// RegisterPanicMapping is goroutine-safe.
+func RegisterPanicMapping(match func(interface{}) bool, code int) {
+	panicMappingsMu.Lock()
+	defer panicMappingsMu.Unlock()
+
+	// Copy on write, so that callers of panicMappingList can safely iterate
+	// over the slice they obtained without holding the lock.
+	mappings := make([]panicMapping, len(panicMappings), len(panicMappings)+1)
+	copy(mappings, panicMappings)
+	panicMappings = append(mappings, panicMapping{match, code})
+}
+
+// panicMappingList returns the current list of panic mappings. The returned
+// slice must not be modified.
+func panicMappingList() []panicMapping {
+	panicMappingsMu.RLock()
+	defer panicMappingsMu.RUnlock()
+	return panicMappings
+}
+
+// PanicCode picks a suitable exit code for the value v returned by recover.
+// If v is nil the returned code is 0.
+//
+// Mappings registered via RegisterPanicMapping are consulted first. If none
+// matches, the following builtin rules apply:
+//
+//   - if v is an error containing an ExitError, its code is returned
+//   - all other values, including runtime.Error values caused by nil pointer
+//     dereferences or out of bounds accesses, produce CodeSoftware
+//
+// Example:
+//
+//   defer func() {
+//     if v := recover(); v != nil {
+//       os.Exit(exit.PanicCode(v))
+//     }
+//   }()
+func PanicCode(v interface{}) int {
+	if v == nil {
+		return CodeOK
+	}
+
+	for _, mapping := range panicMappingList() {
+		if mapping.match(v) {
+			return mapping.code
+		}
+	}
+
+	var exitErr ExitError
+	if err, ok := v.(error); ok && errors.As(err, &exitErr) {
+		return exitErrorCode(exitErr)
+	}
+
+	return CodeSoftware
+}
+
+// panicError converts the recovered panic value v into an ExitError carrying
+// the code obtained via PanicCode. If v is an error it is wrapped, so that
+// errors.Is and errors.As can inspect it.
+func panicError(v interface{}) error {
+	if err, ok := v.(error); ok {
+		return Errorf(PanicCode(v), "panic: %w", err)
+	}
+
+	return Errorf(PanicCode(v), "panic: %v", v)
+}
+
+// ExitOnPanic recovers a panic and exits with the exit code obtained via
+// PanicCode for the panic value, which is CodeSoftware unless the value is an
+// error carrying an exit code or a mapping was registered via
+// RegisterPanicMapping. If there is no panic, ExitOnPanic is a no-op. Must be
+// called directly via defer, usually at the top of main:
+//
+//   func main() {
+//     defer exit.ExitOnPanic()
+//
+//     // do the thing
+//   }
+//
+// If enabled via SetPrintPanicStack, the stack of the panicking goroutine is
+// printed to stderr before exiting.
+//
+// See Exit for more information.
+func ExitOnPanic() {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	if printPanicStack {
+		fmt.Fprintf(errWriter, "panic: %v\n\n%s", v, debug.Stack())
+	}
+
+	Exit(panicError(v))
+}
+
+// SetPrintPanicStack controls whether ExitOnPanic prints the stack of the
+// panicking goroutine to stderr. Disabled by default.
+//
+// Calling SetPrintPanicStack is not goroutine-safe. Should be called early in
+// main.
+func SetPrintPanicStack(enabled bool) {
+	printPanicStack = enabled
+}
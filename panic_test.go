@@ -0,0 +1,154 @@
+package exit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type customPanic struct{}
+
+// panicCode calls fn and returns the PanicCode of the recovered value.
+func panicCode(fn func()) (code int) {
+	defer func() { code = PanicCode(recover()) }()
+	fn()
+	return
+}
+
+func TestPanicCode(t *testing.T) {
+	RegisterPanicMapping(func(v interface{}) bool {
+		_, ok := v.(customPanic)
+		return ok
+	}, CodeTempFail)
+	defer func() { panicMappings = nil }()
+
+	for _, testCase := range []struct {
+		name string
+		fn   func()
+		code int
+	}{
+		{name: "no panic", fn: func() {}, code: CodeOK},
+		{
+			name: "runtime.Error",
+			fn: func() {
+				var m map[string]int
+				m["foo"] = 1
+			},
+			code: CodeSoftware,
+		},
+		{name: "coded error", fn: func() { panic(wrapErr(Error(CodeIOErr, errUntyped))) }, code: CodeIOErr},
+		{name: "untyped error", fn: func() { panic(errUntyped) }, code: CodeSoftware},
+		{name: "string", fn: func() { panic("boom") }, code: CodeSoftware},
+		{name: "custom value", fn: func() { panic(customPanic{}) }, code: CodeTempFail},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := panicCode(testCase.fn); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestExitOnPanic(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		fn   func()
+		code int
+	}{
+		{name: "no panic", fn: func() {}, code: -1},
+		{name: "string", fn: func() { panic("boom") }, code: CodeSoftware},
+		{name: "untyped error", fn: func() { panic(errUntyped) }, code: CodeSoftware},
+		{name: "coded error", fn: func() { panic(wrapErr(Error(CodeIOErr, errUntyped))) }, code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			func() {
+				defer ExitOnPanic()
+				testCase.fn()
+			}()
+
+			if *code != testCase.code {
+				t.Errorf("got code %d, want %d", *code, testCase.code)
+			}
+		})
+	}
+}
+
+func TestExitOnPanic_Error(t *testing.T) {
+	captureExit(t)
+
+	var exitErr error
+
+	SetFinalCodeHook(func(err error, code int) int {
+		exitErr = err
+		return code
+	})
+	defer SetFinalCodeHook(nil)
+
+	func() {
+		defer ExitOnPanic()
+		panic(errUntyped)
+	}()
+
+	if !errors.Is(exitErr, errUntyped) {
+		t.Errorf("errors.Is(%#v, %#v) returned false", exitErr, errUntyped)
+	}
+
+	func() {
+		defer ExitOnPanic()
+		panic(42)
+	}()
+
+	if want := "panic: 42"; exitErr == nil || exitErr.Error() != want {
+		t.Errorf("got %v, want %q", exitErr, want)
+	}
+}
+
+func TestSetPrintPanicStack(t *testing.T) {
+	captureExit(t)
+	buf := captureOutput(t)
+
+	func() {
+		defer ExitOnPanic()
+		panic("boom")
+	}()
+
+	if buf.Len() != 0 {
+		t.Errorf("disabled: got output %q, want none", buf.String())
+	}
+
+	SetPrintPanicStack(true)
+	defer SetPrintPanicStack(false)
+
+	func() {
+		defer ExitOnPanic()
+		panic("boom")
+	}()
+
+	if out := buf.String(); !strings.HasPrefix(out, "panic: boom") || !strings.Contains(out, "TestSetPrintPanicStack") {
+		t.Errorf("enabled: expected panic stack, got %q", out)
+	}
+}
+
+func TestPanicCode_RegisterFromMatcher(t *testing.T) {
+	defer func() { panicMappings = nil }()
+
+	RegisterPanicMapping(func(v interface{}) bool {
+		RegisterPanicMapping(func(interface{}) bool { return false }, CodeSoftware)
+		return v == "boom"
+	}, CodeTempFail)
+
+	done := make(chan int, 1)
+	go func() { done <- PanicCode("boom") }()
+
+	select {
+	case code := <-done:
+		if code != CodeTempFail {
+			t.Errorf("expected code %d, got %d", CodeTempFail, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: PanicCode did not return")
+	}
+}
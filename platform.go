@@ -0,0 +1,64 @@
+package exit
+
+import "runtime"
+
+// Overridden in tests.
+var goos = runtime.GOOS
+
+// ErrorOnOS wraps err with an ExitError that returns given code only if the
+// program is running on the operating system goos (as reported by
+// runtime.GOOS). On all other operating systems, or if err is nil, err is
+// returned as is.
+//
+// Example:
+//
+//   return exit.ErrorOnOS("windows", exit.CodeNoPerm, err)
+//
+// See Error for more information.
+func ErrorOnOS(goos string, code int, err error) error {
+	if !isOS(goos) {
+		return err
+	}
+
+	return Error(code, err)
+}
+
+// isOS reports whether the program is running on the operating system name.
+func isOS(name string) bool {
+	return goos == name
+}
+
+// ErrorPlatform wraps err with an ExitError whose code depends on the
+// operating system the program is running on. Its ExitCode method returns
+// winCode on Windows and unixCode on all other operating systems, including
+// non-Unix ones like Plan 9 or js/wasm. The code is resolved each time
+// ExitCode is called. If err is nil it is returned as is.
+//
+// Example:
+//
+//   return exit.ErrorPlatform(exit.CodeTempFail, exit.CodeErr, err)
+//
+// See Error for more information.
+func ErrorPlatform(unixCode, winCode int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &platformError{err, unixCode, winCode}
+}
+
+type platformError struct {
+	error
+	unixCode int
+	winCode  int
+}
+
+func (e *platformError) Unwrap() error { return e.error }
+
+func (e *platformError) ExitCode() int {
+	if isOS("windows") {
+		return e.winCode
+	}
+
+	return e.unixCode
+}
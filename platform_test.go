@@ -0,0 +1,60 @@
+package exit
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// fakeGOOS replaces goos for the duration of the test.
+func fakeGOOS(t *testing.T, name string) {
+	goos = name
+	t.Cleanup(func() { goos = runtime.GOOS })
+}
+
+func TestErrorOnOS(t *testing.T) {
+	fakeGOOS(t, "windows")
+
+	if err := ErrorOnOS("windows", CodeNoPerm, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	if code := Code(ErrorOnOS("windows", CodeNoPerm, errUntyped)); code != CodeNoPerm {
+		t.Errorf("matching GOOS: got code %d, want %d", code, CodeNoPerm)
+	}
+
+	err := ErrorOnOS("linux", CodeNoPerm, errUntyped)
+	if err != errUntyped {
+		t.Errorf("non-matching GOOS: got %#v, want %#v", err, errUntyped)
+	}
+}
+
+func TestErrorPlatform(t *testing.T) {
+	if err := ErrorPlatform(CodeTempFail, CodeErr, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	err := wrapErr(ErrorPlatform(CodeTempFail, CodeErr, errUntyped))
+
+	for _, testCase := range []struct {
+		goos string
+		code int
+	}{
+		{goos: "windows", code: CodeErr},
+		{goos: "linux", code: CodeTempFail},
+		{goos: "darwin", code: CodeTempFail},
+		{goos: "plan9", code: CodeTempFail},
+	} {
+		t.Run(testCase.goos, func(t *testing.T) {
+			fakeGOOS(t, testCase.goos)
+
+			if code := Code(err); code != testCase.code {
+				t.Errorf("got code %d, want %d", code, testCase.code)
+			}
+		})
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Error("expected chain to be preserved")
+	}
+}
@@ -0,0 +1,64 @@
+package exit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// CodeFromProcessStates returns a single representative exit code for a set of
+// finished processes, e.g. the children of a parallel runner. The result is
+// the highest normalized exit code of all states, or 0 if all processes
+// succeeded. Nil states are ignored.
+//
+// Processes that were terminated by a signal contribute 128+N where N is the
+// signal number, following the shell convention. States which do not carry a
+// valid exit code contribute CodeErr.
+func CodeFromProcessStates(states ...*os.ProcessState) int {
+	code := CodeOK
+
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+
+		if c := processStateCode(state); c > code {
+			code = c
+		}
+	}
+
+	return code
+}
+
+// processStateCode returns the normalized exit code for state.
+func processStateCode(state *os.ProcessState) int {
+	if code := processExitCode(state); code >= 0 {
+		return code
+	}
+
+	return CodeErr
+}
+
+// Run runs cmd and translates its failure into an ExitError. If cmd exits
+// with a non-zero code, the returned error carries the same code, or 128+N if
+// the process was terminated by signal N on Unix platforms. If cmd fails to
+// start or fails otherwise, the returned error carries CodeOSErr. The
+// returned error unwraps to the original error returned by cmd.Run. Returns
+// nil if cmd succeeds.
+//
+// Example:
+//
+//   exit.Exit(exit.Run(exec.Command("make", "test")))
+func Run(cmd *exec.Cmd) error {
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return Error(exitErrorCode(execErr), err)
+	}
+
+	return Error(CodeOSErr, err)
+}
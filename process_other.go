@@ -0,0 +1,12 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package exit
+
+import "os"
+
+// signalCode always returns false as there is no notion of being terminated
+// by a signal on this platform.
+func signalCode(state *os.ProcessState) (int, bool) {
+	return 0, false
+}
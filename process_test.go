@@ -0,0 +1,91 @@
+package exit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+func TestCodeFromProcessStates(t *testing.T) {
+	for _, testCase := range []struct {
+		name   string
+		states []*os.ProcessState
+		code   int
+	}{
+		{name: "no states", code: CodeOK},
+		{name: "nil state", states: []*os.ProcessState{nil}, code: CodeOK},
+		{
+			name:   "all succeeded",
+			states: []*os.ProcessState{processState(t, 0), processState(t, 0)},
+			code:   CodeOK,
+		},
+		{
+			name:   "single failure",
+			states: []*os.ProcessState{processState(t, 0), processState(t, 3), processState(t, 0)},
+			code:   3,
+		},
+		{
+			name:   "multiple failures",
+			states: []*os.ProcessState{processState(t, 3), processState(t, CodeIOErr), nil, processState(t, 0)},
+			code:   CodeIOErr,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := CodeFromProcessStates(testCase.states...); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+// processState runs the TestProcessExitCodeHelper in a subprocess and returns
+// its *os.ProcessState. If code is 0 the helper is run in a mode where it does
+// not call os.Exit and thus exits successfully.
+func processState(t *testing.T, code int) *os.ProcessState {
+	args := []string{"-test.run=TestProcessExitCodeHelper", "--", strconv.Itoa(code)}
+	cmd := exec.Command(os.Args[0], args...)
+	if code != 0 {
+		cmd.Env = []string{"GO_PROCESS_EXIT_CODE_HELPER=1"}
+	}
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			t.Fatalf("failed to run process exit code helper: %v", err)
+		}
+	}
+
+	return cmd.ProcessState
+}
+
+func TestRun(t *testing.T) {
+	helper := func(code int) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=TestProcessExitCodeHelper", "--", strconv.Itoa(code))
+		cmd.Env = []string{"GO_PROCESS_EXIT_CODE_HELPER=1"}
+		return cmd
+	}
+
+	if err := Run(exec.Command(os.Args[0], "-test.run=^$")); err != nil {
+		t.Fatalf("expected nil for successful command, got %v", err)
+	}
+
+	err := Run(helper(CodeIOErr))
+	if code := Code(err); code != CodeIOErr {
+		t.Errorf("expected code %d, got %d", CodeIOErr, code)
+	}
+
+	var execErr *exec.ExitError
+	if !errors.As(err, &execErr) {
+		t.Errorf("expected error to unwrap to *exec.ExitError, got %#v", err)
+	}
+
+	err = Run(exec.Command("/nonexistent/command"))
+	if code := Code(err); code != CodeOSErr {
+		t.Errorf("expected code %d for start failure, got %d", CodeOSErr, code)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected error to unwrap to the start failure, got %v", err)
+	}
+}
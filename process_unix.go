@@ -0,0 +1,20 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalCode returns 128+N if the process described by state was terminated
+// by signal N.
+func signalCode(state *os.ProcessState) (int, bool) {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return 0, false
+	}
+
+	return 128 + int(status.Signal()), true
+}
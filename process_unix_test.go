@@ -0,0 +1,108 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCodeFromProcessStates_Signaled(t *testing.T) {
+	states := []*os.ProcessState{
+		processState(t, 0),
+		processState(t, CodeIOErr),
+		signaledProcessState(t, syscall.SIGKILL),
+	}
+
+	want := 128 + int(syscall.SIGKILL)
+
+	if got := CodeFromProcessStates(states...); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func TestCode_SignaledExecExitError(t *testing.T) {
+	err := wrapErr(&exec.ExitError{ProcessState: signaledProcessState(t, syscall.SIGKILL)})
+
+	if code := Code(err); code != 128+int(syscall.SIGKILL) {
+		t.Errorf("signaled: got %d, want %d", code, 128+int(syscall.SIGKILL))
+	}
+
+	if code := Code(execExitError(3)); code != 3 {
+		t.Errorf("clean exit: got %d, want %d", code, 3)
+	}
+}
+
+func TestConflictingCodes_Signaled(t *testing.T) {
+	err := Error(CodeUnavailable, &exec.ExitError{ProcessState: signaledProcessState(t, syscall.SIGKILL)})
+
+	want := []int{CodeUnavailable, 128 + int(syscall.SIGKILL)}
+
+	if got := ConflictingCodes(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestContextError_Signaled(t *testing.T) {
+	err := ContextError(&exec.ExitError{ProcessState: signaledProcessState(t, syscall.SIGKILL)}, "running %s", "cmd")
+
+	want := 128 + int(syscall.SIGKILL)
+
+	if code := Code(err); code != want {
+		t.Errorf("got code %d, want %d", code, want)
+	}
+
+	if got := ConflictingCodes(err); !reflect.DeepEqual(got, []int{want}) {
+		t.Errorf("got conflicting codes %v, want %v", got, []int{want})
+	}
+}
+
+func TestPanicCode_SignaledExecExitError(t *testing.T) {
+	v := wrapErr(&exec.ExitError{ProcessState: signaledProcessState(t, syscall.SIGKILL)})
+
+	if code, want := PanicCode(v), 128+int(syscall.SIGKILL); code != want {
+		t.Errorf("got code %d, want %d", code, want)
+	}
+}
+
+func TestRun_Signaled(t *testing.T) {
+	err := Run(exec.Command("/bin/sh", "-c", "kill -TERM $$"))
+
+	if code, want := Code(err), 128+int(syscall.SIGTERM); code != want {
+		t.Errorf("got %d, want %d", code, want)
+	}
+}
+
+// TestProcessSignalHelper is a helper which blocks until it gets killed by a
+// signal.
+func TestProcessSignalHelper(t *testing.T) {
+	if os.Getenv("GO_PROCESS_SIGNAL_HELPER") != "1" {
+		return
+	}
+
+	time.Sleep(time.Minute)
+}
+
+// signaledProcessState starts the TestProcessSignalHelper in a subprocess,
+// terminates it with sig and returns its *os.ProcessState.
+func signaledProcessState(t *testing.T, sig syscall.Signal) *os.ProcessState {
+	cmd := exec.Command(os.Args[0], "-test.run=TestProcessSignalHelper")
+	cmd.Env = []string{"GO_PROCESS_SIGNAL_HELPER=1"}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start process signal helper: %v", err)
+	}
+
+	if err := cmd.Process.Signal(sig); err != nil {
+		t.Fatalf("failed to signal process signal helper: %v", err)
+	}
+
+	_ = cmd.Wait()
+
+	return cmd.ProcessState
+}
@@ -0,0 +1,79 @@
+package exit
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry keeps track of exit code ranges reserved by the subsystems of an
+// application, e.g. the commands of a CLI hosting many subsystems. Unlike
+// state configured via package level functions it is instance-scoped, so
+// multiple registries can be used independently. The zero value is an empty
+// registry ready to use. A Registry is goroutine-safe.
+type Registry struct {
+	mu         sync.RWMutex
+	subsystems []subsystem
+}
+
+type subsystem struct {
+	name     string
+	min, max int
+}
+
+// Register reserves the exit codes from min to max (inclusive) for the
+// subsystem name. Returns an error if the range is invalid or overlaps with
+// the range of another subsystem.
+func (r *Registry) Register(name string, min, max int) error {
+	if min > max {
+		return fmt.Errorf("invalid exit code range %d-%d for subsystem %q", min, max, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.subsystems {
+		if min <= s.max && s.min <= max {
+			return fmt.Errorf("exit code range %d-%d for subsystem %q overlaps with range %d-%d of subsystem %q",
+				min, max, name, s.min, s.max, s.name)
+		}
+	}
+
+	r.subsystems = append(r.subsystems, subsystem{name, min, max})
+
+	return nil
+}
+
+// Owner returns the name of the subsystem that reserved code. The second
+// return value is false if code is not part of any reserved range.
+func (r *Registry) Owner(code int) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.subsystems {
+		if code >= s.min && code <= s.max {
+			return s.name, true
+		}
+	}
+
+	return "", false
+}
+
+// Report describes the exit code obtained from err via Code and the
+// subsystem it belongs to, e.g. "subsystem storage: exit code 80". If the code
+// does not belong to any subsystem, only the code is described. If set via
+// SetInstanceTag, the report is prefixed with the instance tag, e.g.
+// "instance shard-1: subsystem storage: exit code 80".
+func (r *Registry) Report(err error) string {
+	code := Code(err)
+
+	report := fmt.Sprintf("exit code %d", code)
+	if name, ok := r.Owner(code); ok {
+		report = fmt.Sprintf("subsystem %s: %s", name, report)
+	}
+
+	if instanceTag != "" {
+		report = fmt.Sprintf("instance %s: %s", instanceTag, report)
+	}
+
+	return report
+}
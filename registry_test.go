@@ -0,0 +1,52 @@
+package exit
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	var r Registry
+
+	if err := r.Register("storage", 80, 89); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Register("network", 90, 99); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "no error", want: "exit code 0"},
+		{name: "storage", err: Error(80, errUntyped), want: "subsystem storage: exit code 80"},
+		{name: "network", err: wrapErr(Error(99, errUntyped)), want: "subsystem network: exit code 99"},
+		{name: "unowned code", err: Error(CodeIOErr, errUntyped), want: "exit code 74"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := r.Report(testCase.err); got != testCase.want {
+				t.Errorf("got %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	var r Registry
+
+	if err := r.Register("storage", 80, 89); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := r.Register("network", 85, 95); err == nil {
+		t.Error("overlapping range: expected error, got nil")
+	}
+
+	if err := r.Register("network", 99, 90); err == nil {
+		t.Error("invalid range: expected error, got nil")
+	}
+
+	if name, ok := r.Owner(85); !ok || name != "storage" {
+		t.Errorf("got owner (%q, %t), want (%q, true)", name, ok, "storage")
+	}
+}
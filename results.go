@@ -0,0 +1,29 @@
+package exit
+
+var allSkippedCode = CodeOK
+
+// CodeForResults computes the exit code for the results of a test-runner-like
+// tool given the number of passed, failed and skipped items:
+//
+//   - CodeErr if there is at least one failure
+//   - the code set via SetAllSkippedCode if all items were skipped
+//   - CodeOK otherwise, including the case where there are no items at all
+func CodeForResults(pass, fail, skip int) int {
+	switch {
+	case fail > 0:
+		return CodeErr
+	case skip > 0 && pass == 0:
+		return allSkippedCode
+	default:
+		return CodeOK
+	}
+}
+
+// SetAllSkippedCode sets the exit code that CodeForResults returns if all
+// items were skipped. Defaults to CodeOK.
+//
+// Calling SetAllSkippedCode is not goroutine-safe. Should be called early in
+// main.
+func SetAllSkippedCode(code int) {
+	allSkippedCode = code
+}
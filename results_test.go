@@ -0,0 +1,39 @@
+package exit
+
+import "testing"
+
+func TestCodeForResults(t *testing.T) {
+	for _, testCase := range []struct {
+		name             string
+		pass, fail, skip int
+		code             int
+	}{
+		{name: "no results", code: CodeOK},
+		{name: "all passed", pass: 3, code: CodeOK},
+		{name: "passed and skipped", pass: 3, skip: 1, code: CodeOK},
+		{name: "single failure", pass: 3, fail: 1, skip: 1, code: CodeErr},
+		{name: "all failed", fail: 2, code: CodeErr},
+		{name: "failed and skipped", fail: 1, skip: 2, code: CodeErr},
+		{name: "all skipped", skip: 2, code: CodeOK},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := CodeForResults(testCase.pass, testCase.fail, testCase.skip)
+			if got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestSetAllSkippedCode(t *testing.T) {
+	SetAllSkippedCode(CodeTempFail)
+	defer SetAllSkippedCode(CodeOK)
+
+	if got := CodeForResults(0, 0, 2); got != CodeTempFail {
+		t.Errorf("all skipped: got %d, want %d", got, CodeTempFail)
+	}
+
+	if got := CodeForResults(1, 0, 2); got != CodeOK {
+		t.Errorf("passed and skipped: got %d, want %d", got, CodeOK)
+	}
+}
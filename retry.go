@@ -0,0 +1,198 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// Overridden in tests.
+var defaultRetryBackoff = time.Second
+
+// IsTemporary reports whether err represents a temporary failure that may go
+// away when retrying. This is the case if Code returns CodeTempFail for err or
+// if err or any error in its chain implements a Temporary() bool method which
+// returns true. Returns false if err is nil.
+//
+// Since Code consults the error handlers set via SetErrorHandler or
+// AddErrorHandler before the builtin rules, an error handler that maps err to
+// CodeTempFail makes IsTemporary return true, while a handler that maps an
+// otherwise temporary error to a different code only affects the code-based
+// check: errors implementing Temporary() bool are still reported as
+// temporary.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+
+	return Code(err) == CodeTempFail
+}
+
+// ShouldRetry reports whether the operation that produced err should be
+// retried. It is equivalent to IsTemporary.
+func ShouldRetry(err error) bool {
+	return IsTemporary(err)
+}
+
+// RetryAfter returns the duration to wait before retrying if err or any error
+// in its chain implements a RetryAfter() time.Duration method. The second
+// return value is false if there is no such hint.
+func RetryAfter(err error) (time.Duration, bool) {
+	var hinter interface{ RetryAfter() time.Duration }
+	if errors.As(err, &hinter) {
+		return hinter.RetryAfter(), true
+	}
+
+	return 0, false
+}
+
+// Retry calls fn until it succeeds, fn returns an error for which ShouldRetry
+// returns false or maxAttempts calls were made. Between attempts it waits for
+// the duration obtained via RetryAfter or one second if the error does not
+// provide a hint. The last error returned by fn is returned, so it can be
+// passed to Exit. Values of maxAttempts smaller than 1 are treated as 1.
+//
+// If ctx is canceled before the first call of fn, ctx.Err() is returned. If it
+// is canceled between attempts, the returned error wraps the last error
+// returned by fn and also matches ctx.Err() via errors.Is. Code thus still
+// uses the exit code of the last error, e.g. that of an ExitError it
+// contains.
+//
+// Example:
+//
+//   err := exit.Retry(ctx, 3, func() error {
+//     return fetch(ctx)
+//   })
+//
+//   exit.Exit(err)
+func Retry(ctx context.Context, maxAttempts int, fn func() error) error {
+	var lastErr error
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			if lastErr != nil {
+				return &retryCanceledError{lastErr, err}
+			}
+
+			return err
+		}
+
+		err := fn()
+		if attempt >= maxAttempts || !ShouldRetry(err) {
+			return err
+		}
+
+		lastErr = err
+
+		backoff, ok := RetryAfter(err)
+		if !ok {
+			backoff = defaultRetryBackoff
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &retryCanceledError{err, ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// retryCanceledError is returned by Retry if ctx is canceled between
+// attempts. It wraps the last error returned by fn and matches the context
+// error via errors.Is.
+type retryCanceledError struct {
+	err    error
+	ctxErr error
+}
+
+func (e *retryCanceledError) Error() string {
+	return e.ctxErr.Error() + ": " + e.err.Error()
+}
+
+func (e *retryCanceledError) Unwrap() error { return e.err }
+
+func (e *retryCanceledError) Is(target error) bool { return errors.Is(e.ctxErr, target) }
+
+// Backoff determines how long to wait before the next attempt of an
+// operation that failed temporarily.
+type Backoff interface {
+	// Backoff returns the duration to wait after the given failed attempt.
+	// Attempts are counted starting at 1.
+	Backoff(attempt int) time.Duration
+}
+
+// BackoffFunc is a function that implements Backoff.
+type BackoffFunc func(attempt int) time.Duration
+
+// Backoff implements Backoff.
+func (f BackoffFunc) Backoff(attempt int) time.Duration { return f(attempt) }
+
+// ConstantBackoff returns a Backoff which always waits for d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return BackoffFunc(func(int) time.Duration { return d })
+}
+
+// ExponentialBackoff returns a Backoff which waits for base after the first
+// attempt and doubles the duration after every further attempt. If maxDelay
+// is positive, durations are capped at maxDelay. Otherwise they are capped at
+// the largest representable time.Duration.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return BackoffFunc(func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			if maxDelay > 0 && d >= maxDelay {
+				break
+			}
+
+			if d > math.MaxInt64/2 {
+				d = math.MaxInt64
+				break
+			}
+			d *= 2
+		}
+
+		if maxDelay > 0 && d > maxDelay {
+			return maxDelay
+		}
+
+		return d
+	})
+}
+
+// RetryWithBackoff calls op until it succeeds, op returns an error for which
+// Code does not return CodeTempFail or attempts calls were made. Between
+// attempts it waits for the duration returned by backoff. A nil backoff
+// retries immediately. The last error returned by op is returned, so it can
+// be passed to Exit. Values of attempts smaller than 1 are treated as 1.
+//
+// Unlike Retry, RetryWithBackoff only considers the exit code of the error
+// and ignores Temporary() methods and RetryAfter hints.
+//
+// Example:
+//
+//   err := exit.RetryWithBackoff(5, exit.ExponentialBackoff(100*time.Millisecond, 5*time.Second), func() error {
+//     return fetch()
+//   })
+//
+//   exit.Exit(err)
+func RetryWithBackoff(attempts int, backoff Backoff, op func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if attempt >= attempts || Code(err) != CodeTempFail {
+			return err
+		}
+
+		if backoff != nil {
+			sleep(backoff.Backoff(attempt))
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type temporaryError struct{ temporary bool }
+
+func (e temporaryError) Error() string   { return "temporary error" }
+func (e temporaryError) Temporary() bool { return e.temporary }
+
+type retryAfterError struct{ after time.Duration }
+
+func (e retryAfterError) Error() string             { return "retry later" }
+func (e retryAfterError) RetryAfter() time.Duration { return e.after }
+
+func TestIsTemporary(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error"},
+		{name: "untyped error", err: errUntyped},
+		{name: "CodeTempFail", err: Error(CodeTempFail, errUntyped), want: true},
+		{name: "wrapped CodeTempFail", err: wrapErr(Error(CodeTempFail, errUntyped)), want: true},
+		{name: "temporary", err: wrapErr(temporaryError{true}), want: true},
+		{name: "not temporary", err: temporaryError{false}},
+		{name: "context.DeadlineExceeded", err: wrapErr(context.DeadlineExceeded), want: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := IsTemporary(testCase.err); got != testCase.want {
+				t.Errorf("got %t, want %t", got, testCase.want)
+			}
+
+			if got := ShouldRetry(testCase.err); got != testCase.want {
+				t.Errorf("ShouldRetry: got %t, want %t", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestIsTemporary_ErrorHandler(t *testing.T) {
+	errRateLimited := errors.New("rate limited")
+	errMaintenance := errors.New("maintenance")
+
+	SetErrorHandler(func(err error) (int, bool) {
+		switch {
+		case errors.Is(err, errRateLimited):
+			return CodeTempFail, true
+		case errors.Is(err, errMaintenance):
+			return CodeUnavailable, true
+		default:
+			return 0, false
+		}
+	})
+	defer SetErrorHandler(nil)
+
+	if !IsTemporary(wrapErr(errRateLimited)) {
+		t.Error("expected error mapped to CodeTempFail by handler to be temporary")
+	}
+
+	if IsTemporary(Error(CodeTempFail, errMaintenance)) {
+		t.Error("expected error mapped to CodeUnavailable by handler not to be temporary")
+	}
+
+	if !IsTemporary(Error(CodeUnavailable, temporaryError{true})) {
+		t.Error("expected error implementing Temporary() to be temporary regardless of its code")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if _, ok := RetryAfter(errUntyped); ok {
+		t.Error("got retry after hint for untyped error")
+	}
+
+	after, ok := RetryAfter(wrapErr(retryAfterError{time.Minute}))
+	if !ok || after != time.Minute {
+		t.Errorf("got (%v, %t), want (%v, true)", after, ok, time.Minute)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	defaultRetryBackoff = time.Millisecond
+	defer func() { defaultRetryBackoff = time.Second }()
+
+	t.Run("success after retry", func(t *testing.T) {
+		var calls int
+
+		err := Retry(context.Background(), 3, func() error {
+			calls++
+			if calls < 3 {
+				return Error(CodeTempFail, errUntyped)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("got %#v, want nil", err)
+		}
+
+		if calls != 3 {
+			t.Errorf("got %d calls, want 3", calls)
+		}
+	})
+
+	t.Run("exhaustion", func(t *testing.T) {
+		var calls int
+
+		err := Retry(context.Background(), 2, func() error {
+			calls++
+			return Error(CodeTempFail, retryAfterError{time.Millisecond})
+		})
+		if code := Code(err); code != CodeTempFail {
+			t.Errorf("got code %d, want %d", code, CodeTempFail)
+		}
+
+		if calls != 2 {
+			t.Errorf("got %d calls, want 2", calls)
+		}
+	})
+
+	t.Run("non-retryable", func(t *testing.T) {
+		var calls int
+
+		err := Retry(context.Background(), 3, func() error {
+			calls++
+			return Error(CodeNoPerm, errUntyped)
+		})
+		if code := Code(err); code != CodeNoPerm {
+			t.Errorf("got code %d, want %d", code, CodeNoPerm)
+		}
+
+		if calls != 1 {
+			t.Errorf("got %d calls, want 1", calls)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := Retry(ctx, 3, func() error {
+			cancel()
+			return Error(CodeTempFail, retryAfterError{time.Minute})
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %#v, want context.Canceled", err)
+		}
+
+		if code := Code(err); code != CodeTempFail {
+			t.Errorf("got code %d, want code %d of the last error", code, CodeTempFail)
+		}
+
+		var hint retryAfterError
+		if !errors.As(err, &hint) {
+			t.Errorf("got %#v, want it to wrap the last error", err)
+		}
+	})
+
+	t.Run("canceled before first attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var calls int
+
+		err := Retry(ctx, 3, func() error {
+			calls++
+			return nil
+		})
+		if err != context.Canceled {
+			t.Errorf("got %#v, want context.Canceled", err)
+		}
+
+		if calls != 0 {
+			t.Errorf("got %d calls, want 0", calls)
+		}
+	})
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	var slept []time.Duration
+
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	defer func() { sleep = time.Sleep }()
+
+	// flakyOp returns an operation that fails temporarily n times before
+	// returning err.
+	flakyOp := func(n int, err error) (func() error, *int) {
+		var calls int
+		return func() error {
+			calls++
+			if calls <= n {
+				return Error(CodeTempFail, errUntyped)
+			}
+			return err
+		}, &calls
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		attempts int
+		backoff  Backoff
+		failures int
+		err      error
+		code     int
+		calls    int
+		slept    []time.Duration
+	}{
+		{
+			name:     "immediate success",
+			attempts: 3,
+			backoff:  ConstantBackoff(time.Second),
+			code:     CodeOK,
+			calls:    1,
+		},
+		{
+			name:     "success after temporary failures",
+			attempts: 3,
+			backoff:  ConstantBackoff(time.Second),
+			failures: 2,
+			code:     CodeOK,
+			calls:    3,
+			slept:    []time.Duration{time.Second, time.Second},
+		},
+		{
+			name:     "exhaustion",
+			attempts: 3,
+			backoff:  ExponentialBackoff(time.Second, 0),
+			failures: 5,
+			code:     CodeTempFail,
+			calls:    3,
+			slept:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:     "non-temporary error",
+			attempts: 5,
+			backoff:  ConstantBackoff(time.Second),
+			failures: 1,
+			err:      Error(CodeNoPerm, errUntyped),
+			code:     CodeNoPerm,
+			calls:    2,
+			slept:    []time.Duration{time.Second},
+		},
+		{
+			name:     "nil backoff",
+			attempts: 2,
+			failures: 5,
+			code:     CodeTempFail,
+			calls:    2,
+		},
+		{
+			name:     "attempts smaller than 1",
+			backoff:  ConstantBackoff(time.Second),
+			failures: 5,
+			code:     CodeTempFail,
+			calls:    1,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			slept = nil
+
+			op, calls := flakyOp(testCase.failures, testCase.err)
+
+			err := RetryWithBackoff(testCase.attempts, testCase.backoff, op)
+			if code := Code(err); code != testCase.code {
+				t.Errorf("got code %d, want %d", code, testCase.code)
+			}
+
+			if *calls != testCase.calls {
+				t.Errorf("got %d calls, want %d", *calls, testCase.calls)
+			}
+
+			if !reflect.DeepEqual(slept, testCase.slept) {
+				t.Errorf("got backoffs %v, want %v", slept, testCase.slept)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		if got := backoff.Backoff(attempt); got != want {
+			t.Errorf("attempt %d: got %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestExponentialBackoff_NoMaxDelay(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 0)
+
+	prev := backoff.Backoff(1)
+
+	for attempt := 2; attempt <= 100; attempt++ {
+		got := backoff.Backoff(attempt)
+		if got < prev {
+			t.Fatalf("attempt %d: got %v, want at least %v", attempt, got, prev)
+		}
+
+		prev = got
+	}
+
+	if want := time.Duration(math.MaxInt64); prev != want {
+		t.Errorf("got %v, want %v", prev, want)
+	}
+}
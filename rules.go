@@ -0,0 +1,67 @@
+package exit
+
+import "errors"
+
+// Rule maps errors for which Match returns true to Code.
+type Rule struct {
+	Match func(error) bool
+	Code  int
+}
+
+// CodeByRules returns the Code of the first rule in rules that matches err.
+// If no rule matches, the exit code is obtained via Code. Returns CodeOK if err
+// is nil without consulting any rule.
+//
+// This is a local alternative to SetErrorHandler for code that wants to
+// classify errors explicitly without mutating global state:
+//
+//   code := exit.CodeByRules(err,
+//     exit.Rule{Match: isNotFound, Code: exit.CodeNoInput},
+//     exit.Rule{Match: isTimeout, Code: exit.CodeTempFail},
+//   )
+func CodeByRules(err error, rules ...Rule) int {
+	if err == nil {
+		return CodeOK
+	}
+
+	for _, rule := range rules {
+		if rule.Match(err) {
+			return rule.Code
+		}
+	}
+
+	return Code(err)
+}
+
+// SentinelRule maps errors matching Err via errors.Is to Code.
+type SentinelRule struct {
+	Err  error
+	Code int
+}
+
+// CodeFor returns the Code of the first rule in rules whose Err matches err
+// via errors.Is. Rules are checked in order, so if err matches multiple
+// sentinels, e.g. because one wraps the other, the first rule wins. If no
+// rule matches, the exit code is obtained via Code. Returns CodeOK if err is
+// nil without consulting any rule.
+//
+// Rules take precedence over everything else, including error handlers,
+// explicit codes of ExitError values and the builtin rules:
+//
+//   code := exit.CodeFor(err, []exit.SentinelRule{
+//     {Err: ErrNotFound, Code: exit.CodeNoInput},
+//     {Err: io.ErrUnexpectedEOF, Code: exit.CodeDataErr},
+//   })
+func CodeFor(err error, rules []SentinelRule) int {
+	if err == nil {
+		return CodeOK
+	}
+
+	for _, rule := range rules {
+		if errors.Is(err, rule.Err) {
+			return rule.Code
+		}
+	}
+
+	return Code(err)
+}
@@ -0,0 +1,69 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCodeByRules(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	rules := []Rule{
+		{Match: func(err error) bool { return errors.Is(err, errFirst) }, Code: CodeNoInput},
+		{Match: func(err error) bool { return errors.Is(err, errSecond) }, Code: CodeTempFail},
+		{Match: func(err error) bool { return errors.Is(err, errSecond) }, Code: CodeUnavailable},
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "first rule matches", err: wrapErr(errFirst), code: CodeNoInput},
+		{name: "later rule matches", err: errSecond, code: CodeTempFail},
+		{name: "no rule matches", err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "no rule matches untyped", err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := CodeByRules(testCase.err, rules...); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestCodeFor(t *testing.T) {
+	errBase := errors.New("base")
+	errSpecific := fmt.Errorf("specific: %w", errBase)
+	errOther := errors.New("other")
+
+	rules := []SentinelRule{
+		{Err: errSpecific, Code: CodeNoInput},
+		{Err: errBase, Code: CodeDataErr},
+		{Err: errOther, Code: CodeTempFail},
+		{Err: errOther, Code: CodeUnavailable},
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "specific sentinel wins over base", err: wrapErr(errSpecific), code: CodeNoInput},
+		{name: "base sentinel", err: wrapErr(errBase), code: CodeDataErr},
+		{name: "first of duplicate rules wins", err: errOther, code: CodeTempFail},
+		{name: "rule wins over ExitError", err: Error(CodeIOErr, errBase), code: CodeDataErr},
+		{name: "no rule matches", err: Error(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "no rule matches untyped", err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := CodeFor(testCase.err, rules); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package exit
+
+import "errors"
+
+// Sentinel errors for the sysexits codes. Each of them implements ExitError
+// with the corresponding code and uses the code's description as message.
+// They can be returned directly, wrapped via fmt.Errorf and the %w verb and
+// compared using errors.Is:
+//
+//   if errors.Is(err, exit.ErrNoPerm) {
+//     // handle permission error
+//   }
+var (
+	ErrUsage       = newSentinel(CodeUsage)
+	ErrDataErr     = newSentinel(CodeDataErr)
+	ErrNoInput     = newSentinel(CodeNoInput)
+	ErrNoUser      = newSentinel(CodeNoUser)
+	ErrNoHost      = newSentinel(CodeNoHost)
+	ErrUnavailable = newSentinel(CodeUnavailable)
+	ErrSoftware    = newSentinel(CodeSoftware)
+	ErrOSErr       = newSentinel(CodeOSErr)
+	ErrOSFile      = newSentinel(CodeOSFile)
+	ErrCantCreat   = newSentinel(CodeCantCreat)
+	ErrIOErr       = newSentinel(CodeIOErr)
+	ErrTempFail    = newSentinel(CodeTempFail)
+	ErrProtocol    = newSentinel(CodeProtocol)
+	ErrNoPerm      = newSentinel(CodeNoPerm)
+	ErrConfig      = newSentinel(CodeConfig)
+)
+
+func newSentinel(code int) error {
+	return &exitError{errors.New(codeInfos[code].description), code}
+}
+
+// FromCode returns an ExitError carrying code with a message describing the
+// code, e.g. "exit code 74 (IOErr: input/output error)". This is useful to
+// reconstruct an error from the exit status of a subprocess. For codes which
+// do not correspond to any of the Code* constants the message is just
+// "exit code <code>". Returns nil for CodeOK.
+//
+// See Describe for more information.
+func FromCode(code int) error {
+	if code == CodeOK {
+		return nil
+	}
+
+	if _, ok := codeInfos[code]; !ok {
+		return Errorf(code, "exit code %d", code)
+	}
+
+	return Errorf(code, "exit code %d (%s)", code, Describe(code))
+}
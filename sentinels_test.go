@@ -0,0 +1,87 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSentinels(t *testing.T) {
+	for _, testCase := range []struct {
+		err  error
+		code int
+		msg  string
+	}{
+		{err: ErrUsage, code: CodeUsage, msg: "command line usage error"},
+		{err: ErrDataErr, code: CodeDataErr, msg: "data format error"},
+		{err: ErrNoInput, code: CodeNoInput, msg: "cannot open input"},
+		{err: ErrNoUser, code: CodeNoUser, msg: "addressee unknown"},
+		{err: ErrNoHost, code: CodeNoHost, msg: "host name unknown"},
+		{err: ErrUnavailable, code: CodeUnavailable, msg: "service unavailable"},
+		{err: ErrSoftware, code: CodeSoftware, msg: "internal software error"},
+		{err: ErrOSErr, code: CodeOSErr, msg: "system error (e.g., can't fork)"},
+		{err: ErrOSFile, code: CodeOSFile, msg: "critical OS file missing"},
+		{err: ErrCantCreat, code: CodeCantCreat, msg: "can't create (user) output file"},
+		{err: ErrIOErr, code: CodeIOErr, msg: "input/output error"},
+		{err: ErrTempFail, code: CodeTempFail, msg: "temp failure; user is invited to retry"},
+		{err: ErrProtocol, code: CodeProtocol, msg: "remote error in protocol"},
+		{err: ErrNoPerm, code: CodeNoPerm, msg: "permission denied"},
+		{err: ErrConfig, code: CodeConfig, msg: "configuration error"},
+	} {
+		t.Run(testCase.msg, func(t *testing.T) {
+			if exitErr, ok := testCase.err.(ExitError); !ok {
+				t.Errorf("got %#v, want ExitError", testCase.err)
+			} else if code := exitErr.ExitCode(); code != testCase.code {
+				t.Errorf("got ExitError with code %d, want %d", code, testCase.code)
+			}
+
+			if testCase.err.Error() != testCase.msg {
+				t.Errorf("got msg %q, want %q", testCase.err.Error(), testCase.msg)
+			}
+
+			wrapped := fmt.Errorf("context: %w", testCase.err)
+
+			if code := Code(wrapped); code != testCase.code {
+				t.Errorf("wrapped: got code %d, want %d", code, testCase.code)
+			}
+
+			if !errors.Is(wrapped, testCase.err) {
+				t.Errorf("errors.Is(%#v, %#v) returned false", wrapped, testCase.err)
+			}
+		})
+	}
+
+	if errors.Is(ErrNoPerm, ErrNoInput) {
+		t.Error("expected distinct sentinels to not match via errors.Is")
+	}
+}
+
+func TestFromCode(t *testing.T) {
+	if err := FromCode(CodeOK); err != nil {
+		t.Fatalf("expected nil for CodeOK, got %v", err)
+	}
+
+	for code := range codeInfos {
+		if code == CodeOK {
+			continue
+		}
+
+		err := FromCode(code)
+		if got := Code(err); got != code {
+			t.Errorf("expected code %d, got %d", code, got)
+		}
+
+		if msg, want := err.Error(), fmt.Sprintf("exit code %d (%s)", code, Describe(code)); msg != want {
+			t.Errorf("expected message %q, got %q", want, msg)
+		}
+	}
+
+	err := FromCode(42)
+	if code := Code(err); code != 42 {
+		t.Errorf("expected code 42, got %d", code)
+	}
+
+	if msg := err.Error(); msg != "exit code 42" {
+		t.Errorf("expected generic message, got %q", msg)
+	}
+}
@@ -0,0 +1,26 @@
+package exit
+
+// Sources of settings accepted by SettingsError.
+const (
+	SettingsSourceFile  = "file"
+	SettingsSourceFlags = "flags"
+)
+
+// SettingsError wraps err, which occurred while loading settings from source,
+// into an ExitError. Errors caused by command line flags (source
+// SettingsSourceFlags) are usage errors and produce CodeUsage, errors from
+// all other sources, e.g. configuration files (source SettingsSourceFile),
+// produce CodeConfig. The source is included in the error message. If err is
+// nil it is returned as is.
+func SettingsError(source string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	code := CodeConfig
+	if source == SettingsSourceFlags {
+		code = CodeUsage
+	}
+
+	return Errorf(code, "invalid settings from %s: %w", source, err)
+}
@@ -0,0 +1,39 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSettingsError(t *testing.T) {
+	if err := SettingsError(SettingsSourceFile, nil); err != nil {
+		t.Errorf("got %#v, want nil", err)
+	}
+
+	for _, testCase := range []struct {
+		name   string
+		source string
+		code   int
+		msg    string
+	}{
+		{name: "file", source: SettingsSourceFile, code: CodeConfig, msg: "invalid settings from file: error"},
+		{name: "flags", source: SettingsSourceFlags, code: CodeUsage, msg: "invalid settings from flags: error"},
+		{name: "other", source: "env", code: CodeConfig, msg: "invalid settings from env: error"},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := SettingsError(testCase.source, errUntyped)
+
+			if code := Code(err); code != testCase.code {
+				t.Errorf("got code %d, want %d", code, testCase.code)
+			}
+
+			if err.Error() != testCase.msg {
+				t.Errorf("got msg %q, want %q", err.Error(), testCase.msg)
+			}
+
+			if !errors.Is(err, errUntyped) {
+				t.Errorf("errors.Is(%#v, %#v) returned false", err, errUntyped)
+			}
+		})
+	}
+}
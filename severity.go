@@ -0,0 +1,95 @@
+package exit
+
+// severityOrder lists exit codes from least to most severe. Codes not
+// contained in the list are as severe as CodeErr.
+var severityOrder = []int{
+	CodeOK,
+	CodeHelpErr,
+	CodeErr,
+	CodeInterrupt,
+	CodeTerminated,
+	CodeTempFail,
+	CodeUnavailable,
+	CodeUsage,
+	CodeDataErr,
+	CodeNoInput,
+	CodeNoUser,
+	CodeNoHost,
+	CodeProtocol,
+	CodeCantCreat,
+	CodeIOErr,
+	CodeNoPerm,
+	CodeConfig,
+	CodeOSFile,
+	CodeOSErr,
+	CodeSoftware,
+}
+
+// severities maps exit codes to their index in severityOrder.
+var severities = func() map[int]int {
+	m := make(map[int]int, len(severityOrder))
+	for i, code := range severityOrder {
+		m[code] = i
+	}
+	return m
+}()
+
+// Severity returns the severity of code as an ordinal which can be compared
+// with the severity of other codes. Higher values denote more severe codes.
+// Codes are ordered by severity as follows, from least to most severe:
+//
+//   CodeOK < CodeHelpErr < CodeErr < CodeInterrupt < CodeTerminated <
+//   CodeTempFail < CodeUnavailable < CodeUsage < CodeDataErr < CodeNoInput <
+//   CodeNoUser < CodeNoHost < CodeProtocol < CodeCantCreat < CodeIOErr <
+//   CodeNoPerm < CodeConfig < CodeOSFile < CodeOSErr < CodeSoftware
+//
+// Successful and informational outcomes are the least severe, followed by
+// interruptions and transient failures which may succeed on retry. Errors
+// caused by invalid input come next, then failures of the environment and
+// finally internal software errors. All other codes are as severe as CodeErr.
+func Severity(code int) int {
+	if s, ok := severities[code]; ok {
+		return s
+	}
+
+	return severities[CodeErr]
+}
+
+// MoreSevere returns the more severe of the codes a and b according to
+// Severity. If both are equally severe, a is returned.
+func MoreSevere(a, b int) int {
+	if Severity(b) > Severity(a) {
+		return b
+	}
+
+	return a
+}
+
+// Escalate attaches code to err only if code is more severe than all codes of
+// ExitError values already contained in err's chain. Otherwise the most severe
+// code already present is attached instead, so that an outer layer suggesting
+// a generic code does not mask a more specific code of an inner layer:
+//
+//   err := exit.Escalate(exit.CodeErr, exit.Error(exit.CodeNoPerm, err))
+//   exit.Code(err) // CodeNoPerm
+//
+// In both cases err is wrapped, so its message and chain are preserved. If err
+// is nil it is returned as is.
+//
+// See Severity for the order of codes by severity.
+func Escalate(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	walkErrors(err, func(err error) bool {
+		if exitErr, ok := err.(ExitError); ok {
+			if c := exitErrorCode(exitErr); Severity(c) >= Severity(code) {
+				code = c
+			}
+		}
+		return true
+	})
+
+	return &exitError{err, code}
+}
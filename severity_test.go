@@ -0,0 +1,107 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEscalate(t *testing.T) {
+	if err := Escalate(CodeSoftware, nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	if msg := Escalate(CodeErr, wrapErr(Error(CodeNoPerm, errUntyped))).Error(); msg != "wrapped: error" {
+		t.Errorf("expected message to be preserved, got %q", msg)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "uncoded error", err: Escalate(CodeIOErr, errUntyped), code: CodeIOErr},
+		{name: "outer more severe", err: Escalate(CodeSoftware, Error(CodeUsage, errUntyped)), code: CodeSoftware},
+		{name: "outer less severe", err: Escalate(CodeErr, Error(CodeNoPerm, errUntyped)), code: CodeNoPerm},
+		{name: "equal severity", err: Escalate(CodeIOErr, wrapErr(Error(CodeIOErr, errUntyped))), code: CodeIOErr},
+		{name: "unknown code is as severe as CodeErr", err: Escalate(42, Error(CodeErr, errUntyped)), code: CodeErr},
+		{
+			name: "nested escalations upwards",
+			err:  Escalate(CodeConfig, wrapErr(Escalate(CodeIOErr, Escalate(CodeTempFail, errUntyped)))),
+			code: CodeConfig,
+		},
+		{
+			name: "nested escalations downwards",
+			err:  Escalate(CodeErr, wrapErr(Escalate(CodeUsage, Escalate(CodeSoftware, errUntyped)))),
+			code: CodeSoftware,
+		},
+		{
+			name: "most severe of multiple inner codes",
+			err:  Escalate(CodeUsage, Error(CodeTempFail, wrapErr(Error(CodeOSErr, wrapErr(Error(CodeDataErr, errUntyped)))))),
+			code: CodeOSErr,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+
+			if !errors.Is(testCase.err, errUntyped) {
+				t.Error("expected chain to be preserved")
+			}
+
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	order := []int{
+		CodeOK,
+		CodeHelpErr,
+		CodeErr,
+		CodeInterrupt,
+		CodeTerminated,
+		CodeTempFail,
+		CodeUnavailable,
+		CodeUsage,
+		CodeDataErr,
+		CodeNoInput,
+		CodeNoUser,
+		CodeNoHost,
+		CodeProtocol,
+		CodeCantCreat,
+		CodeIOErr,
+		CodeNoPerm,
+		CodeConfig,
+		CodeOSFile,
+		CodeOSErr,
+		CodeSoftware,
+	}
+
+	if len(order) != len(codeInfos) {
+		t.Fatalf("expected all %d codes to be ordered, got %d", len(codeInfos), len(order))
+	}
+
+	for i := 1; i < len(order); i++ {
+		less, more := order[i-1], order[i]
+
+		if Severity(less) >= Severity(more) {
+			t.Errorf("expected %s to be less severe than %s", CodeString(less), CodeString(more))
+		}
+
+		if got := MoreSevere(less, more); got != more {
+			t.Errorf("MoreSevere(%s, %s): got %s, want %s", CodeString(less), CodeString(more), CodeString(got), CodeString(more))
+		}
+
+		if got := MoreSevere(more, less); got != more {
+			t.Errorf("MoreSevere(%s, %s): got %s, want %s", CodeString(more), CodeString(less), CodeString(got), CodeString(more))
+		}
+	}
+
+	if Severity(42) != Severity(CodeErr) {
+		t.Errorf("expected unknown code to be as severe as CodeErr")
+	}
+
+	if got := MoreSevere(42, CodeErr); got != 42 {
+		t.Errorf("expected first code for equal severity, got %d", got)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build go1.21
+// +build go1.21
+
+package exit
+
+import "log/slog"
+
+// SlogHandler returns an ExitLogFunc which logs the error passed to Exit
+// together with the exit code to logger at error level. Nothing is logged if
+// the program exits successfully. Use it with SetExitLogFunc:
+//
+//   exit.SetExitLogFunc(exit.SlogHandler(slog.Default()))
+//
+// This produces a log record equivalent to:
+//
+//   logger.Error("exiting", "code", code, "err", err)
+//
+// If set via SetInstanceTag, the record additionally has an "instance"
+// attribute.
+func SlogHandler(logger *slog.Logger) ExitLogFunc {
+	return func(err error, code int) {
+		if err == nil && code == CodeOK {
+			return
+		}
+
+		if instanceTag != "" {
+			logger.Error("exiting", "code", code, "err", err, "instance", instanceTag)
+			return
+		}
+
+		logger.Error("exiting", "code", code, "err", err)
+	}
+}
@@ -0,0 +1,98 @@
+//go:build go1.21
+// +build go1.21
+
+package exit
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// recordingHandler is a slog.Handler that records all handled records.
+type recordingHandler struct {
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestSlogHandler(t *testing.T) {
+	handler := &recordingHandler{}
+
+	SetExitLogFunc(SlogHandler(slog.New(handler)))
+	defer SetExitLogFunc(nil)
+
+	code := captureExit(t)
+
+	Exit(nil)
+
+	if len(handler.records) != 0 {
+		t.Fatalf("expected no records on success, got %d", len(handler.records))
+	}
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if *code != CodeIOErr {
+		t.Errorf("expected code %d, got %d", CodeIOErr, *code)
+	}
+
+	if len(handler.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(handler.records))
+	}
+
+	record := handler.records[0]
+
+	if record.Level != slog.LevelError || record.Message != "exiting" {
+		t.Errorf("unexpected record: %v %q", record.Level, record.Message)
+	}
+
+	attrs := make(map[string]slog.Value)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+
+	if got := attrs["code"].Int64(); got != CodeIOErr {
+		t.Errorf("expected code attribute %d, got %d", CodeIOErr, got)
+	}
+
+	if got := attrs["err"].String(); got != "error" {
+		t.Errorf("expected err attribute %q, got %q", "error", got)
+	}
+}
+
+func TestSlogHandler_InstanceTag(t *testing.T) {
+	handler := &recordingHandler{}
+
+	SetExitLogFunc(SlogHandler(slog.New(handler)))
+	defer SetExitLogFunc(nil)
+
+	captureExit(t)
+	setInstanceTag(t, "shard-1")
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if len(handler.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(handler.records))
+	}
+
+	var instance string
+	handler.records[0].Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "instance" {
+			instance = attr.Value.String()
+		}
+		return true
+	})
+
+	if instance != "shard-1" {
+		t.Errorf("expected instance attribute %q, got %q", "shard-1", instance)
+	}
+}
@@ -0,0 +1,55 @@
+package exit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+var (
+	exitSocket        string
+	exitSocketTimeout = time.Second
+)
+
+// SetExitSocket configures Exit to send a JSON encoded exit event to the Unix
+// socket at path before exiting, e.g. to inform a local supervisor about the
+// outcome of the program. The event has the following form:
+//
+//   {"code":74,"error":"read foo: input/output error"}
+//
+// The error field is omitted if Exit is called with a nil error. If set via
+// SetInstanceTag, the event includes an instance field. Connecting
+// and writing to the socket is subject to a short timeout. Failures are
+// ignored and never change the exit code. Passing an empty path disables
+// sending of exit events, which is the default. Exit events are only
+// supported on Unix platforms and silently dropped on others.
+//
+// Calling SetExitSocket is not goroutine-safe. Should be called early in main.
+func SetExitSocket(path string) {
+	exitSocket = path
+}
+
+type exitEvent struct {
+	Code     int    `json:"code"`
+	Error    string `json:"error,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// sendExitEvent sends the exit event for err and code to the socket
+// configured via SetExitSocket.
+func sendExitEvent(err error, code int) {
+	if exitSocket == "" {
+		return
+	}
+
+	event := exitEvent{Code: code, Instance: instanceTag}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	buf, jsonErr := json.Marshal(event)
+	if jsonErr != nil {
+		return
+	}
+
+	writeExitSocket(exitSocket, append(buf, '\n'))
+}
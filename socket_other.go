@@ -0,0 +1,8 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package exit
+
+// writeExitSocket is a no-op as exit events are only supported on Unix
+// platforms.
+func writeExitSocket(path string, buf []byte) {}
@@ -0,0 +1,24 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"net"
+	"time"
+)
+
+// writeExitSocket writes buf to the Unix socket at path. Errors are ignored.
+func writeExitSocket(path string, buf []byte) {
+	conn, err := net.DialTimeout("unix", path, exitSocketTimeout)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(exitSocketTimeout)); err != nil {
+		return
+	}
+
+	_, _ = conn.Write(buf)
+}
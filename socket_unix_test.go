@@ -0,0 +1,98 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+// listenExitSocket listens on a Unix socket in a temporary directory and
+// configures it via SetExitSocket. The returned channel receives the data of
+// the first connection.
+func listenExitSocket(t *testing.T) <-chan string {
+	path := filepath.Join(t.TempDir(), "exit.sock")
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen on %s: %v", path, err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	SetExitSocket(path)
+	t.Cleanup(func() { SetExitSocket("") })
+
+	ch := make(chan string, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(ch)
+			return
+		}
+		defer conn.Close()
+
+		buf, _ := ioutil.ReadAll(conn)
+		ch <- string(buf)
+	}()
+
+	return ch
+}
+
+func TestSetExitSocket(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "no error", want: `{"code":0}` + "\n"},
+		{
+			name: "ExitError",
+			err:  Error(CodeIOErr, errUntyped),
+			want: `{"code":74,"error":"error"}` + "\n",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			ch := listenExitSocket(t)
+			code := captureExit(t)
+
+			Exit(testCase.err)
+
+			if got := <-ch; got != testCase.want {
+				t.Errorf("got event %q, want %q", got, testCase.want)
+			}
+
+			if want := Code(testCase.err); *code != want {
+				t.Errorf("got code %d, want %d", *code, want)
+			}
+		})
+	}
+}
+
+func TestSetExitSocket_InstanceTag(t *testing.T) {
+	ch := listenExitSocket(t)
+	captureExit(t)
+	setInstanceTag(t, "shard-1")
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if got, want := <-ch, `{"code":74,"error":"error","instance":"shard-1"}`+"\n"; got != want {
+		t.Errorf("got event %q, want %q", got, want)
+	}
+}
+
+func TestSetExitSocket_Unreachable(t *testing.T) {
+	SetExitSocket(filepath.Join(t.TempDir(), "nonexistent.sock"))
+	defer SetExitSocket("")
+
+	code := captureExit(t)
+
+	Exit(Error(CodeIOErr, errUntyped))
+
+	if *code != CodeIOErr {
+		t.Errorf("got code %d, want %d", *code, CodeIOErr)
+	}
+}
@@ -0,0 +1,37 @@
+package exit
+
+import "runtime"
+
+// maxStackDepth is the maximum number of frames recorded by ErrorStack.
+const maxStackDepth = 32
+
+// StackTracer is implemented by errors which recorded the stack of their
+// creation, e.g. those created via ErrorStack.
+type StackTracer interface {
+	StackTrace() []uintptr
+}
+
+// ErrorStack is like Error but additionally records the call stack at the
+// point where it is invoked. This helps to find out where an exit code got
+// attached to an error. The recorded program counters can be obtained via the
+// StackTrace method of the returned error and resolved via
+// runtime.CallersFrames. If err is nil it is returned as is and no stack is
+// recorded.
+func ErrorStack(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers and ErrorStack itself.
+	n := runtime.Callers(2, pcs)
+
+	return &stackError{exitError{err, code}, pcs[:n]}
+}
+
+type stackError struct {
+	exitError
+	stack []uintptr
+}
+
+func (e *stackError) StackTrace() []uintptr { return e.stack }
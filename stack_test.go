@@ -0,0 +1,36 @@
+package exit
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestErrorStack(t *testing.T) {
+	if err := ErrorStack(CodeIOErr, nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	err := wrapErr(ErrorStack(CodeIOErr, errUntyped))
+
+	if code := Code(err); code != CodeIOErr {
+		t.Errorf("expected code %d, got %d", CodeIOErr, code)
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Error("expected errors.Is to find the wrapped error")
+	}
+
+	var tracer StackTracer
+	if !errors.As(err, &tracer) {
+		t.Fatal("expected error to implement StackTracer")
+	}
+
+	frames := runtime.CallersFrames(tracer.StackTrace())
+
+	frame, _ := frames.Next()
+	if !strings.HasSuffix(frame.Function, ".TestErrorStack") {
+		t.Errorf("expected first frame to be the caller, got %q", frame.Function)
+	}
+}
@@ -0,0 +1,25 @@
+package exit
+
+// Steps runs steps in order and exits on the first step that returns a
+// non-nil error using the exit code obtained from that error. The remaining
+// steps are not run. If all steps succeed this is equivalent to os.Exit(0).
+//
+// Example:
+//
+//   exit.Steps(
+//     loadConfig,
+//     connect,
+//     func() error { return run(ctx) },
+//   )
+//
+// See Exit for more information.
+func Steps(steps ...func() error) {
+	for _, step := range steps {
+		if err := step(); err != nil {
+			Exit(err)
+			return
+		}
+	}
+
+	Exit(nil)
+}
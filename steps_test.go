@@ -0,0 +1,42 @@
+package exit
+
+import "testing"
+
+func TestSteps(t *testing.T) {
+	code := captureExit(t)
+
+	var ran []int
+
+	Steps(
+		func() error { ran = append(ran, 1); return nil },
+		func() error { ran = append(ran, 2); return Error(CodeIOErr, errUntyped) },
+		func() error { ran = append(ran, 3); return nil },
+	)
+
+	if *code != CodeIOErr {
+		t.Errorf("got code %d, want %d", *code, CodeIOErr)
+	}
+
+	if len(ran) != 2 || ran[0] != 1 || ran[1] != 2 {
+		t.Errorf("got steps %v to run, want [1 2]", ran)
+	}
+}
+
+func TestSteps_Success(t *testing.T) {
+	code := captureExit(t)
+
+	var ran int
+
+	Steps(
+		func() error { ran++; return nil },
+		func() error { ran++; return nil },
+	)
+
+	if *code != CodeOK {
+		t.Errorf("got code %d, want %d", *code, CodeOK)
+	}
+
+	if ran != 2 {
+		t.Errorf("got %d steps to run, want 2", ran)
+	}
+}
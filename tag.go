@@ -0,0 +1,60 @@
+package exit
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// Tagged is a marker that can be embedded into error types in order to
+// declare their exit code via an `exit` struct tag instead of implementing
+// ExitError:
+//
+//   type NotFoundError struct {
+//     exit.Tagged `exit:"66"`
+//     Name string
+//   }
+//
+// See CodeFromTaggedError for more information.
+type Tagged struct{}
+
+func (Tagged) exitTagged() {}
+
+type taggedError interface {
+	error
+	exitTagged()
+}
+
+var taggedType = reflect.TypeOf(Tagged{})
+
+// CodeFromTaggedError finds the first error in err's chain that embeds
+// Tagged and returns the exit code declared via the `exit` struct tag on the
+// embedded field. The second return value is false if there is no such error
+// or if the tag is missing or does not contain a valid integer.
+func CodeFromTaggedError(err error) (int, bool) {
+	var tagged taggedError
+	if !errors.As(err, &tagged) {
+		return 0, false
+	}
+
+	typ := reflect.TypeOf(tagged)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	field, ok := typ.FieldByName(taggedType.Name())
+	if !ok || !field.Anonymous || field.Type != taggedType {
+		return 0, false
+	}
+
+	code, convErr := strconv.Atoi(field.Tag.Get("exit"))
+	if convErr != nil {
+		return 0, false
+	}
+
+	return code, true
+}
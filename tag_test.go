@@ -0,0 +1,38 @@
+package exit
+
+import "testing"
+
+type taggedNotFoundError struct {
+	Tagged `exit:"66"`
+	Name   string
+}
+
+func (e *taggedNotFoundError) Error() string { return e.Name + " not found" }
+
+type untaggedError struct {
+	Tagged
+}
+
+func (e untaggedError) Error() string { return "untagged" }
+
+func TestCodeFromTaggedError(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+		ok   bool
+	}{
+		{name: "no error"},
+		{name: "untyped error", err: errUntyped},
+		{name: "tagged error", err: &taggedNotFoundError{Name: "foo"}, code: CodeNoInput, ok: true},
+		{name: "wrapped tagged error", err: wrapErr(&taggedNotFoundError{Name: "foo"}), code: CodeNoInput, ok: true},
+		{name: "marker without tag", err: untaggedError{}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code, ok := CodeFromTaggedError(testCase.err)
+			if code != testCase.code || ok != testCase.ok {
+				t.Errorf("got (%d, %t), want (%d, %t)", code, ok, testCase.code, testCase.ok)
+			}
+		})
+	}
+}
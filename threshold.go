@@ -0,0 +1,37 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CodeForThreshold returns CodeOK if value reaches threshold and CodeErr
+// otherwise. This is useful for quality gates, e.g. for checking code
+// coverage.
+func CodeForThreshold(value, threshold float64) int {
+	if value >= threshold {
+		return CodeOK
+	}
+
+	return CodeErr
+}
+
+// ExitOnThreshold exits with CodeErr if value does not reach threshold after
+// printing a message built from format and args to stderr. Returns normally
+// otherwise.
+//
+// Example:
+//
+//   exit.ExitOnThreshold(coverage, 80, "coverage %.1f%% below threshold %.1f%%", coverage, 80.0)
+//
+// See Exit for more information.
+func ExitOnThreshold(value, threshold float64, format string, args ...interface{}) {
+	code := CodeForThreshold(value, threshold)
+	if code == CodeOK {
+		return
+	}
+
+	err := Error(code, errors.New(fmt.Sprintf(format, args...)))
+
+	exit(err, code, osExit, printError(errWriter, err))
+}
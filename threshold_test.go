@@ -0,0 +1,42 @@
+package exit
+
+import "testing"
+
+func TestCodeForThreshold(t *testing.T) {
+	for _, testCase := range []struct {
+		name             string
+		value, threshold float64
+		code             int
+	}{
+		{name: "pass", value: 81.5, threshold: 80, code: CodeOK},
+		{name: "fail", value: 78, threshold: 80, code: CodeErr},
+		{name: "exact boundary", value: 80, threshold: 80, code: CodeOK},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := CodeForThreshold(testCase.value, testCase.threshold); got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+		})
+	}
+}
+
+func TestExitOnThreshold(t *testing.T) {
+	code := captureExit(t)
+	buf := captureOutput(t)
+
+	ExitOnThreshold(80, 80, "coverage %.1f%% below threshold %.1f%%", 80.0, 80.0)
+
+	if *code != -1 || buf.Len() != 0 {
+		t.Errorf("pass: expected no exit and output, got code %d and output %q", *code, buf.String())
+	}
+
+	ExitOnThreshold(78, 80, "coverage %.1f%% below threshold %.1f%%", 78.0, 80.0)
+
+	if *code != CodeErr {
+		t.Errorf("fail: got code %d, want %d", *code, CodeErr)
+	}
+
+	if want := "coverage 78.0% below threshold 80.0%\n"; buf.String() != want {
+		t.Errorf("fail: got output %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,55 @@
+package exit
+
+// CodeTranslator translates exit codes of this package into the exit codes
+// of an application specific enum and back. Unlike a global error handler it
+// is instance-scoped and does not mutate any package state, which makes it
+// suitable for use in libraries.
+type CodeTranslator struct {
+	codes   map[int]int
+	reverse map[int]int
+}
+
+// NewCodeTranslator creates a new *CodeTranslator. The keys of codes are exit
+// codes as produced by Code, the values are the application's codes they
+// translate to. Codes that are not present in the map are passed through
+// unchanged.
+func NewCodeTranslator(codes map[int]int) *CodeTranslator {
+	t := &CodeTranslator{
+		codes:   make(map[int]int, len(codes)),
+		reverse: make(map[int]int, len(codes)),
+	}
+
+	for from, to := range codes {
+		t.codes[from] = to
+		t.reverse[to] = from
+	}
+
+	return t
+}
+
+// Translate computes the exit code for err via Code and translates it into
+// the application's code.
+func (t *CodeTranslator) Translate(err error) int {
+	return t.TranslateCode(Code(err))
+}
+
+// TranslateCode translates code into the application's code. If there is no
+// mapping for code it is returned as is.
+func (t *CodeTranslator) TranslateCode(code int) int {
+	if translated, ok := t.codes[code]; ok {
+		return translated
+	}
+
+	return code
+}
+
+// Reverse translates the application's code back into the exit code of this
+// package. If there is no mapping for code it is returned as is. If multiple
+// codes translate to the same application code, the result is unspecified.
+func (t *CodeTranslator) Reverse(code int) int {
+	if original, ok := t.reverse[code]; ok {
+		return original
+	}
+
+	return code
+}
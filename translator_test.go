@@ -0,0 +1,45 @@
+package exit
+
+import "testing"
+
+func TestCodeTranslator(t *testing.T) {
+	translator := NewCodeTranslator(map[int]int{
+		CodeIOErr:  10,
+		CodeNoPerm: 11,
+	})
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "mapped code", err: Error(CodeIOErr, errUntyped), code: 10},
+		{name: "wrapped mapped code", err: wrapErr(Error(CodeNoPerm, errUntyped)), code: 11},
+		{name: "unmapped code", err: Error(CodeConfig, errUntyped), code: CodeConfig},
+		{name: "untyped error", err: errUntyped, code: CodeErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := translator.Translate(testCase.err)
+			if got != testCase.code {
+				t.Errorf("got %d, want %d", got, testCase.code)
+			}
+
+			if original := translator.Reverse(got); original != Code(testCase.err) {
+				t.Errorf("round-trip: got %d, want %d", original, Code(testCase.err))
+			}
+		})
+	}
+}
+
+func TestCodeTranslator_Reverse(t *testing.T) {
+	translator := NewCodeTranslator(map[int]int{CodeIOErr: 10})
+
+	if got := translator.Reverse(10); got != CodeIOErr {
+		t.Errorf("got %d, want %d", got, CodeIOErr)
+	}
+
+	if got := translator.Reverse(CodeConfig); got != CodeConfig {
+		t.Errorf("got %d, want %d", got, CodeConfig)
+	}
+}
@@ -0,0 +1,125 @@
+package exit
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+type typeMapping struct {
+	target error
+	typ    reflect.Type // non-nil if target is matched via errors.As
+	code   int
+}
+
+var (
+	typeMappingsMu sync.RWMutex
+	typeMappings   []typeMapping
+)
+
+// RegisterType registers a mapping from errors matching target to code. This
+// allows to declare the exit code of error types without writing error
+// handlers:
+//
+//   exit.RegisterType(&ValidationError{}, exit.CodeDataErr)
+//   exit.RegisterType(ErrNotConfigured, exit.CodeConfig)
+//
+// How target is matched depends on its value:
+//
+//   - if target is the zero value of a struct type or a pointer to the zero
+//     value of a struct type, e.g. &ValidationError{}, it is used as a type
+//     template: errors matching via errors.As against a target of the same
+//     type produce code
+//   - all other targets, e.g. sentinel errors created via errors.New, are
+//     matched via errors.Is
+//
+// Mappings are consulted by Code after error handlers and explicit codes
+// carried by ExitError values, but before code extractors and the remaining
+// builtin rules. If multiple mappings match, the one registered first wins.
+// Passing a nil target is a no-op.
+//
+// RegisterType is goroutine-safe.
+func RegisterType(target error, code int) {
+	if target == nil {
+		return
+	}
+
+	mapping := typeMapping{target: target, code: code}
+
+	if isTypeTemplate(target) {
+		mapping.typ = reflect.TypeOf(target)
+	}
+
+	addTypeMapping(mapping)
+}
+
+// RegisterNotFound registers err as a sentinel signaling that something was
+// not found, e.g. sql.ErrNoRows. Errors matching err via errors.Is produce
+// CodeNoInput:
+//
+//   exit.RegisterNotFound(sql.ErrNoRows)
+//
+// Unlike RegisterType, err is always matched via errors.Is, even if it is the
+// zero value of a struct type. Multiple sentinels can be registered by calling
+// RegisterNotFound repeatedly. The registrations share the order of
+// precedence with mappings registered via RegisterType. Passing nil is a no-op.
+//
+// RegisterNotFound is goroutine-safe.
+func RegisterNotFound(err error) {
+	if err == nil {
+		return
+	}
+
+	addTypeMapping(typeMapping{target: err, code: CodeNoInput})
+}
+
+func addTypeMapping(mapping typeMapping) {
+	typeMappingsMu.Lock()
+	defer typeMappingsMu.Unlock()
+
+	// Copy on write, so that callers of typeMappingList can safely iterate
+	// over the slice they obtained without holding the lock.
+	mappings := make([]typeMapping, len(typeMappings), len(typeMappings)+1)
+	copy(mappings, typeMappings)
+	typeMappings = append(mappings, mapping)
+}
+
+// typeMappingList returns the current list of type mappings. The returned
+// slice must not be modified.
+func typeMappingList() []typeMapping {
+	typeMappingsMu.RLock()
+	defer typeMappingsMu.RUnlock()
+	return typeMappings
+}
+
+// typeCode returns the code of the first mapping registered via RegisterType
+// that matches err. Matching happens without holding the lock, since Is and As
+// methods of err may register mappings themselves.
+func typeCode(err error) (int, bool) {
+	for _, mapping := range typeMappingList() {
+		if mapping.typ != nil {
+			if errors.As(err, reflect.New(mapping.typ).Interface()) {
+				return mapping.code, true
+			}
+		} else if errors.Is(err, mapping.target) {
+			return mapping.code, true
+		}
+	}
+
+	return 0, false
+}
+
+// isTypeTemplate reports whether target is the zero value of a struct type or
+// a pointer to one.
+func isTypeTemplate(target error) bool {
+	v := reflect.ValueOf(target)
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+
+	return v.Kind() == reflect.Struct && v.IsZero()
+}
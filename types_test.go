@@ -0,0 +1,131 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type validationError struct {
+	Field string
+}
+
+func (e *validationError) Error() string { return "invalid field " + e.Field }
+
+type valueError struct{}
+
+func (valueError) Error() string { return "value error" }
+
+// resetTypeMappings removes all mappings registered via RegisterType and
+// RegisterNotFound at the end of the test.
+func resetTypeMappings(t *testing.T) {
+	t.Cleanup(func() {
+		typeMappingsMu.Lock()
+		typeMappings = nil
+		typeMappingsMu.Unlock()
+	})
+}
+
+func TestRegisterType(t *testing.T) {
+	resetTypeMappings(t)
+
+	errNotConfigured := errors.New("not configured")
+
+	RegisterType(&validationError{}, CodeDataErr)
+	RegisterType(valueError{}, CodeProtocol)
+	RegisterType(errNotConfigured, CodeConfig)
+	RegisterType(nil, CodeSoftware)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "no error", code: CodeOK},
+		{name: "unregistered error", err: errUntyped, code: CodeErr},
+		{name: "pointer type", err: &validationError{Field: "name"}, code: CodeDataErr},
+		{name: "wrapped pointer type", err: wrapErr(&validationError{Field: "name"}), code: CodeDataErr},
+		{name: "value type", err: wrapErr(valueError{}), code: CodeProtocol},
+		{name: "sentinel", err: errNotConfigured, code: CodeConfig},
+		{name: "wrapped sentinel", err: wrapErr(errNotConfigured), code: CodeConfig},
+		{name: "other sentinel of same type", err: errors.New("not configured"), code: CodeErr},
+		{name: "ExitError takes precedence", err: Error(CodeIOErr, &validationError{}), code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+func TestRegisterType_FirstMatchWins(t *testing.T) {
+	resetTypeMappings(t)
+
+	RegisterType(&validationError{}, CodeDataErr)
+	RegisterType(&validationError{}, CodeUsage)
+
+	if code := Code(&validationError{}); code != CodeDataErr {
+		t.Errorf("expected code %d, got %d", CodeDataErr, code)
+	}
+}
+
+func TestRegisterNotFound(t *testing.T) {
+	resetTypeMappings(t)
+
+	errNoRows := errors.New("sql: no rows in result set")
+	errNoSuchKey := errors.New("no such key")
+
+	RegisterNotFound(errNoRows)
+	RegisterNotFound(errNoSuchKey)
+	RegisterNotFound(valueError{})
+	RegisterNotFound(nil)
+
+	for _, testCase := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "sentinel", err: errNoRows, code: CodeNoInput},
+		{name: "wrapped sentinel", err: fmt.Errorf("query user: %w", wrapErr(errNoRows)), code: CodeNoInput},
+		{name: "second sentinel", err: wrapErr(errNoSuchKey), code: CodeNoInput},
+		{name: "zero value matched via errors.Is", err: wrapErr(valueError{}), code: CodeNoInput},
+		{name: "other error", err: errUntyped, code: CodeErr},
+		{name: "exit error wins", err: Error(CodeUnavailable, errNoRows), code: CodeUnavailable},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if code := Code(testCase.err); code != testCase.code {
+				t.Errorf("expected code %d, got %d", testCase.code, code)
+			}
+		})
+	}
+}
+
+// registeringError registers a type mapping from within its Is method.
+type registeringError struct{}
+
+func (registeringError) Error() string { return "registering error" }
+
+func (registeringError) Is(target error) bool {
+	RegisterType(errors.New("registered while matching"), CodeSoftware)
+	return false
+}
+
+func TestRegisterType_FromIsMethod(t *testing.T) {
+	resetTypeMappings(t)
+
+	RegisterType(errUntyped, CodeConfig)
+
+	done := make(chan int, 1)
+	go func() { done <- Code(registeringError{}) }()
+
+	select {
+	case code := <-done:
+		if code != CodeErr {
+			t.Errorf("expected code %d, got %d", CodeErr, code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock: Code did not return")
+	}
+}
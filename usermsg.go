@@ -0,0 +1,59 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UserMessager is implemented by errors which carry a friendly message for
+// end users next to their technical error message.
+type UserMessager interface {
+	UserMessage() string
+}
+
+// UserMessage returns the user-facing message of the first error in err's
+// chain that implements UserMessager. Returns an empty string if there is
+// none.
+func UserMessage(err error) string {
+	var messager UserMessager
+	if errors.As(err, &messager) {
+		return messager.UserMessage()
+	}
+
+	return ""
+}
+
+// ErrorMsg creates a new ExitError with given exit code which carries userMsg
+// as a friendly message for end users. The message of the returned error
+// consists of userMsg followed by ": " and the message of cause, so that the
+// full technical details can be logged, while the user message can be
+// printed to stderr:
+//
+//   if msg := exit.UserMessage(err); msg != "" {
+//     fmt.Fprintln(os.Stderr, msg)
+//   }
+//
+//   log.Print(err)
+//
+// The returned error unwraps to cause. If cause is nil, the message of the
+// returned error is just userMsg.
+func ErrorMsg(code int, userMsg string, cause error) error {
+	var err error
+	if cause == nil {
+		err = errors.New(userMsg)
+	} else {
+		err = fmt.Errorf("%s: %w", userMsg, cause)
+	}
+
+	return &userMsgError{exitError{err, code}, userMsg, cause}
+}
+
+type userMsgError struct {
+	exitError
+	userMsg string
+	cause   error
+}
+
+func (e *userMsgError) Unwrap() error { return e.cause }
+
+func (e *userMsgError) UserMessage() string { return e.userMsg }
@@ -0,0 +1,57 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorMsg(t *testing.T) {
+	cause := wrapErr(errUntyped)
+	err := ErrorMsg(CodeConfig, "the config file is invalid", cause)
+
+	if msg := err.Error(); msg != "the config file is invalid: wrapped: error" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	if msg := UserMessage(wrapErr(err)); msg != "the config file is invalid" {
+		t.Errorf("unexpected user message: %q", msg)
+	}
+
+	if unwrapped := errors.Unwrap(err); unwrapped != cause {
+		t.Errorf("expected errors.Unwrap to return the cause, got %v", unwrapped)
+	}
+
+	if !errors.Is(err, errUntyped) {
+		t.Error("expected errors.Is to find the wrapped error")
+	}
+
+	if code := Code(err); code != CodeConfig {
+		t.Errorf("expected code %d, got %d", CodeConfig, code)
+	}
+}
+
+func TestErrorMsg_NilCause(t *testing.T) {
+	err := ErrorMsg(CodeUsage, "missing argument", nil)
+
+	if msg := err.Error(); msg != "missing argument" {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	if unwrapped := errors.Unwrap(err); unwrapped != nil {
+		t.Errorf("expected no cause, got %v", unwrapped)
+	}
+
+	if code := Code(err); code != CodeUsage {
+		t.Errorf("expected code %d, got %d", CodeUsage, code)
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	if msg := UserMessage(errUntyped); msg != "" {
+		t.Errorf("expected empty user message, got %q", msg)
+	}
+
+	if msg := UserMessage(nil); msg != "" {
+		t.Errorf("expected empty user message, got %q", msg)
+	}
+}
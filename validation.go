@@ -0,0 +1,38 @@
+package exit
+
+import "strings"
+
+// Validator is implemented by types that collect validation errors, e.g. the
+// result of validating command line arguments.
+type Validator interface {
+	Errors() []error
+}
+
+// CodeFromValidation returns CodeUsage if v reports any validation errors and
+// CodeOK otherwise.
+func CodeFromValidation(v Validator) int {
+	if len(v.Errors()) > 0 {
+		return CodeUsage
+	}
+
+	return CodeOK
+}
+
+// ExitOnValidation exits with CodeUsage if v reports any validation errors.
+// Returns normally otherwise. The error passed to Exit contains the messages
+// of all validation errors.
+//
+// See Exit for more information.
+func ExitOnValidation(v Validator) {
+	errs := v.Errors()
+	if len(errs) == 0 {
+		return
+	}
+
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	Exit(Errorf(CodeUsage, "validation failed: %s", strings.Join(msgs, "; ")))
+}
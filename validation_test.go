@@ -0,0 +1,46 @@
+package exit
+
+import (
+	"errors"
+	"testing"
+)
+
+type fakeValidator []error
+
+func (v fakeValidator) Errors() []error { return v }
+
+func TestCodeFromValidation(t *testing.T) {
+	if code := CodeFromValidation(fakeValidator{}); code != CodeOK {
+		t.Errorf("valid: got code %d, want %d", code, CodeOK)
+	}
+
+	v := fakeValidator{errors.New("foo is required"), errors.New("bar must be positive")}
+
+	if code := CodeFromValidation(v); code != CodeUsage {
+		t.Errorf("invalid: got code %d, want %d", code, CodeUsage)
+	}
+}
+
+func TestExitOnValidation(t *testing.T) {
+	code := captureExit(t)
+	buf := captureOutput(t)
+
+	SetExitBanner(true)
+	defer SetExitBanner(false)
+
+	ExitOnValidation(fakeValidator(nil))
+
+	if *code != -1 {
+		t.Errorf("valid: expected no exit, got code %d", *code)
+	}
+
+	ExitOnValidation(fakeValidator{errors.New("foo is required"), errors.New("bar must be positive")})
+
+	if *code != CodeUsage {
+		t.Errorf("invalid: got code %d, want %d", *code, CodeUsage)
+	}
+
+	if buf.Len() == 0 {
+		t.Error("expected exit banner to be printed")
+	}
+}
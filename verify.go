@@ -0,0 +1,36 @@
+package exit
+
+import "fmt"
+
+// VerifyMappings checks the exit codes of all registered mappings and returns
+// an error for each mapping that produces a code outside of the valid range
+// 0-255. It is meant to be used as a sanity check at startup after all
+// mappings were registered. Returns nil if all mappings are valid.
+//
+// Only mappings with a statically known exit code can be verified, i.e.
+// mappings registered via RegisterType, RegisterNotFound and
+// RegisterPanicMapping. Codes produced by opaque funcs, like error handlers or
+// code extractors, cannot be verified.
+func VerifyMappings() []error {
+	var errs []error
+
+	for i, mapping := range typeMappingList() {
+		if !isValidCode(mapping.code) {
+			errs = append(errs, fmt.Errorf("type mapping %d (%T): exit code %d is out of range 0-255", i, mapping.target, mapping.code))
+		}
+	}
+
+	for i, mapping := range panicMappingList() {
+		if !isValidCode(mapping.code) {
+			errs = append(errs, fmt.Errorf("panic mapping %d: exit code %d is out of range 0-255", i, mapping.code))
+		}
+	}
+
+	return errs
+}
+
+// isValidCode reports whether code is within the range of exit codes that
+// are supported on all platforms.
+func isValidCode(code int) bool {
+	return code >= 0 && code <= 255
+}
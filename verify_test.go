@@ -0,0 +1,49 @@
+package exit
+
+import "testing"
+
+func TestVerifyMappings(t *testing.T) {
+	defer func() { panicMappings = nil }()
+
+	match := func(interface{}) bool { return false }
+
+	RegisterPanicMapping(match, CodeSoftware)
+
+	if errs := VerifyMappings(); len(errs) != 0 {
+		t.Errorf("got errors %v, want none", errs)
+	}
+
+	RegisterPanicMapping(match, 999)
+	RegisterPanicMapping(match, -1)
+
+	errs := VerifyMappings()
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+
+	if want := "panic mapping 1: exit code 999 is out of range 0-255"; errs[0].Error() != want {
+		t.Errorf("got %q, want %q", errs[0].Error(), want)
+	}
+}
+
+func TestVerifyMappings_TypeMappings(t *testing.T) {
+	resetTypeMappings(t)
+
+	RegisterType(&validationError{}, CodeDataErr)
+	RegisterNotFound(errUntyped)
+
+	if errs := VerifyMappings(); len(errs) != 0 {
+		t.Errorf("got errors %v, want none", errs)
+	}
+
+	RegisterType(&validationError{}, 999)
+
+	errs := VerifyMappings()
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errs)
+	}
+
+	if want := "type mapping 2 (*exit.validationError): exit code 999 is out of range 0-255"; errs[0].Error() != want {
+		t.Errorf("got %q, want %q", errs[0].Error(), want)
+	}
+}
@@ -0,0 +1,94 @@
+package exit
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// WrapMain wraps run into a func suitable to be used as a program's main:
+//
+//   func main() {
+//     exit.WrapMain(run)()
+//   }
+//
+//   func run(ctx context.Context, args []string) error {
+//     // do the thing
+//   }
+//
+// The returned func calls run with os.Args[1:] and a context that is canceled
+// when the program receives an interrupt signal. It exits with the exit code
+// computed from the error returned by run, which includes running all
+// functions registered via OnExit. If run returns the error of the canceled
+// context after an interrupt, the exit code is CodeInterrupt. Panics in run
+// are recovered and produce the exit code obtained via PanicCode, which is
+// CodeSoftware by default.
+//
+// See Exit for more information.
+func WrapMain(run func(ctx context.Context, args []string) error) func() {
+	return func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, os.Interrupt)
+		defer signal.Stop(sigCh)
+
+		go func() {
+			select {
+			case <-sigCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		Exit(runMain(ctx, run))
+	}
+}
+
+// runMain calls run with os.Args[1:] and converts panics into errors carrying
+// the exit code obtained via PanicCode.
+func runMain(ctx context.Context, run func(ctx context.Context, args []string) error) error {
+	return callMain(func() error { return run(ctx, os.Args[1:]) })
+}
+
+// RunMain calls fn and returns the exit code computed from the error it
+// returns instead of exiting. Panics in fn are recovered and produce the exit
+// code obtained via PanicCode. Like Exit, RunMain applies the hook set via
+// SetFinalCodeHook to the exit code. Unlike Exit, it neither prints the error
+// nor runs the functions registered via OnExit. This makes it possible to
+// test a program's main logic without replacing os.Exit:
+//
+//   func main() {
+//     os.Exit(exit.RunMain(run))
+//   }
+//
+//   func TestRun(t *testing.T) {
+//     if code := exit.RunMain(run); code != exit.CodeOK {
+//       t.Errorf("unexpected exit code %d", code)
+//     }
+//   }
+//
+// See Code for more information on how the exit code is determined.
+func RunMain(fn func() error) int {
+	err := callMain(fn)
+
+	code := Code(err)
+	if finalCodeHook != nil {
+		code = finalCodeHook(err, code)
+	}
+
+	return code
+}
+
+// callMain calls fn and converts panics into errors carrying the exit code
+// obtained via PanicCode.
+func callMain(fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = panicError(v)
+		}
+	}()
+
+	return fn()
+}
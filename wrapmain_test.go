@@ -0,0 +1,115 @@
+package exit
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestWrapMain(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		run  func(ctx context.Context, args []string) error
+		code int
+	}{
+		{
+			name: "success",
+			run: func(ctx context.Context, args []string) error {
+				if !reflect.DeepEqual(args, os.Args[1:]) {
+					t.Errorf("got args %v, want %v", args, os.Args[1:])
+				}
+				return nil
+			},
+			code: CodeOK,
+		},
+		{
+			name: "error",
+			run: func(ctx context.Context, args []string) error {
+				return Error(CodeNoInput, errUntyped)
+			},
+			code: CodeNoInput,
+		},
+		{
+			name: "panic",
+			run: func(ctx context.Context, args []string) error {
+				panic("boom")
+			},
+			code: CodeSoftware,
+		},
+		{
+			name: "panic with coded error",
+			run: func(ctx context.Context, args []string) error {
+				panic(Error(CodeIOErr, errUntyped))
+			},
+			code: CodeIOErr,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			var cleanedUp bool
+
+			OnExit(func() { cleanedUp = true })
+
+			WrapMain(testCase.run)()
+
+			if *code != testCase.code {
+				t.Errorf("got code %d, want %d", *code, testCase.code)
+			}
+
+			if !cleanedUp {
+				t.Error("expected OnExit functions to run")
+			}
+		})
+	}
+}
+
+func TestRunMain(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		fn   func() error
+		code int
+	}{
+		{name: "success", fn: func() error { return nil }, code: CodeOK},
+		{name: "uncoded error", fn: func() error { return errUntyped }, code: CodeErr},
+		{name: "exit error", fn: func() error { return Error(CodeNoInput, errUntyped) }, code: CodeNoInput},
+		{name: "wrapped exit error", fn: func() error { return wrapErr(Error(CodeConfig, errUntyped)) }, code: CodeConfig},
+		{name: "help", fn: func() error { return flag.ErrHelp }, code: CodeHelpErr},
+		{name: "context canceled", fn: func() error { return context.Canceled }, code: CodeInterrupt},
+		{name: "not exist", fn: func() error { return os.ErrNotExist }, code: CodeNoInput},
+		{name: "permission", fn: func() error { return os.ErrPermission }, code: CodeNoPerm},
+		{name: "net error", fn: func() error { return &fakeNetError{} }, code: CodeUnavailable},
+		{name: "panic", fn: func() error { panic("boom") }, code: CodeSoftware},
+		{name: "panic with coded error", fn: func() error { panic(Error(CodeIOErr, errUntyped)) }, code: CodeIOErr},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			code := captureExit(t)
+
+			if got := RunMain(testCase.fn); got != testCase.code {
+				t.Errorf("got code %d, want %d", got, testCase.code)
+			}
+
+			if *code != -1 {
+				t.Errorf("expected RunMain not to exit, got exit code %d", *code)
+			}
+		})
+	}
+}
+
+func TestRunMain_FinalCodeHook(t *testing.T) {
+	SetFinalCodeHook(func(err error, code int) int {
+		if errors.Is(err, errUntyped) && code == CodeIOErr {
+			return CodeTempFail
+		}
+
+		return code
+	})
+	defer SetFinalCodeHook(nil)
+
+	if got := RunMain(func() error { return Error(CodeIOErr, errUntyped) }); got != CodeTempFail {
+		t.Errorf("got code %d, want %d", got, CodeTempFail)
+	}
+}
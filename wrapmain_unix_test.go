@@ -0,0 +1,28 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package exit
+
+import (
+	"context"
+	"syscall"
+	"testing"
+)
+
+func TestWrapMain_Interrupt(t *testing.T) {
+	code := captureExit(t)
+
+	WrapMain(func(ctx context.Context, args []string) error {
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+			t.Fatalf("failed to send SIGINT: %v", err)
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})()
+
+	if *code != CodeInterrupt {
+		t.Errorf("got code %d, want %d", *code, CodeInterrupt)
+	}
+}
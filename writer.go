@@ -0,0 +1,63 @@
+package exit
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// defaultErrWriter is the process-wide destination for messages printed by
+// this package. It can be swapped via SetErrorWriter.
+var defaultErrWriter = &syncWriter{w: os.Stderr}
+
+// syncWriter is an io.Writer which serializes writes to the underlying
+// writer and allows to swap it concurrently. Writes are discarded if the
+// underlying writer is nil.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w == nil {
+		return len(p), nil
+	}
+
+	return s.w.Write(p)
+}
+
+func (s *syncWriter) set(w io.Writer) {
+	s.mu.Lock()
+	s.w = w
+	s.mu.Unlock()
+}
+
+// enabled reports whether an underlying writer is set.
+func (s *syncWriter) enabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w != nil
+}
+
+// SetErrorWriter sets the destination for error messages printed by Fatal,
+// Exitf and other functions of this package which report errors before
+// exiting. Defaults to os.Stderr. If w is nil, printing is suppressed.
+//
+// Writes to w are serialized, so w does not need to be safe for concurrent
+// use itself. SetErrorWriter is goroutine-safe.
+//
+// Example:
+//
+//   logFile, err := os.OpenFile("error.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+//   if err != nil {
+//     exit.Fatal(err)
+//   }
+//
+//   exit.SetErrorWriter(io.MultiWriter(os.Stderr, logFile))
+func SetErrorWriter(w io.Writer) {
+	defaultErrWriter.set(w)
+}
@@ -0,0 +1,85 @@
+package exit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+)
+
+// captureErrorWriter sets w via SetErrorWriter for the duration of the test.
+func captureErrorWriter(t *testing.T, w *bytes.Buffer) {
+	SetErrorWriter(w)
+	t.Cleanup(func() { SetErrorWriter(os.Stderr) })
+}
+
+func TestSetErrorWriter(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "error", err: Error(CodeIOErr, errors.New("disk on fire")), want: "disk on fire\n"},
+		{name: "nil error", err: nil, want: ""},
+		{name: "empty message", err: Error(CodeIOErr, errors.New("")), want: ""},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			captureExit(t)
+			captureErrorWriter(t, &buf)
+
+			Fatal(testCase.err)
+
+			if got := buf.String(); got != testCase.want {
+				t.Errorf("got output %q, want %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSetErrorWriter_Nil(t *testing.T) {
+	code := captureExit(t)
+
+	SetErrorWriter(nil)
+	t.Cleanup(func() { SetErrorWriter(os.Stderr) })
+
+	Fatal(Error(CodeIOErr, errUntyped))
+
+	if *code != CodeIOErr {
+		t.Errorf("got code %d, want %d", *code, CodeIOErr)
+	}
+
+	if LastExitPrinted() {
+		t.Error("expected LastExitPrinted to return false with printing suppressed")
+	}
+}
+
+func TestSetErrorWriter_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+
+	captureErrorWriter(t, &buf)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			printError(errWriter, errUntyped)
+		}()
+
+		go func() {
+			defer wg.Done()
+			SetErrorWriter(&buf)
+		}()
+	}
+
+	wg.Wait()
+
+	if want := bytes.Repeat([]byte("error\n"), 10); !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got output %q, want %q", buf.String(), want)
+	}
+}